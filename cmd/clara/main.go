@@ -5,20 +5,44 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Embedded timezone database for CLINIC_TIMEZONE
 
-	_ "github.com/mattn/go-sqlite3" // SQLite driver for whatsmeow session storage
+	_ "github.com/mattn/go-sqlite3" // SQLite driver for whatsmeow session and sqlite storage
 
 	"github.com/matheusmassa1/clara/internal/config"
-	"github.com/matheusmassa1/clara/internal/repository/mongo"
+	"github.com/matheusmassa1/clara/internal/handler"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/reminder"
+	"github.com/matheusmassa1/clara/internal/retention"
+	"github.com/matheusmassa1/clara/internal/schedule"
+	"github.com/matheusmassa1/clara/internal/service"
 	"github.com/matheusmassa1/clara/internal/whatsapp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// subcommands run instead of the assistant when named as first argument
+var subcommands = map[string]func(args []string) error{
+	"migrate": runMigrate,
+	"export":  runExport,
+	"erase":   runErase,
+}
+
 func main() {
 	// Setup structured logging
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
+	// Subcommands
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal().Err(err).Str("command", os.Args[1]).Msg("Command failed")
+			}
+			return
+		}
+	}
+
 	log.Info().Msg("Starting Clara WhatsApp Assistant")
 
 	// Load configuration
@@ -36,37 +60,61 @@ func main() {
 	zerolog.SetGlobalLevel(level)
 
 	log.Info().
+		Str("storage", cfg.StorageBackend).
 		Str("db_name", cfg.DBName).
+		Str("nlp_engine", cfg.NLPEngine).
 		Str("intent_model", cfg.HFIntentModel).
 		Str("ner_model", cfg.HFNERModel).
 		Str("session_dir", cfg.SessionDir).
 		Msg("Configuration loaded successfully")
 
-	// Connect to MongoDB
+	// Connect to storage
 	ctx := context.Background()
-	client, db, err := mongo.Connect(ctx, cfg.MongoURI, cfg.DBName)
+	repos, closeStorage, err := openRepositories(ctx, cfg)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to MongoDB")
+		log.Fatal().Err(err).Str("storage", cfg.StorageBackend).Msg("Failed to open storage")
 	}
-	defer func() {
-		if err := mongo.Disconnect(context.Background(), client); err != nil {
-			log.Error().Err(err).Msg("Failed to disconnect from MongoDB")
-		}
-	}()
+	defer closeStorage()
+
+	// Initialize NLP
+	classifier, err := nlp.NewClassifier(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create intent classifier")
+	}
+	extractor, err := nlp.NewExtractor(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create entity extractor")
+	}
+
+	// Initialize scheduling
+	clinicSchedule, err := schedule.FromConfig(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load clinic schedule")
+	}
+	availability := schedule.NewAvailability(clinicSchedule, repos.appointments)
+
+	// Initialize services
+	patients := service.NewPatientService(repos.patients)
+	scheduling := service.NewSchedulingService(repos.appointments, availability)
+	reminders := service.NewReminderService(repos.reminders, patients)
+	privacy := service.NewPrivacyService(repos.patients, repos.appointments, repos.reminders,
+		repos.sessions, repos.audit, scheduling)
 
-	// Ensure indexes exist
-	if err := mongo.EnsureIndexes(ctx, db); err != nil {
-		log.Fatal().Err(err).Msg("Failed to ensure MongoDB indexes")
+	reminderOffsets, err := reminder.ParseOffsets(cfg.ReminderOffsets)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid REMINDER_OFFSETS")
 	}
 
-	// Create repository instances
-	patientRepo := mongo.NewPatientRepository(db)
-	appointmentRepo := mongo.NewAppointmentRepository(db)
-	_ = patientRepo      // prevent unused variable error (future phases)
-	_ = appointmentRepo  // prevent unused variable error (future phases)
+	// Build message handler chain
+	msgHandler := handler.NewChain(
+		handler.NewPrivacyHandler(patients, privacy, repos.sessions),
+		handler.NewBookingHandler(patients, scheduling, repos.sessions, classifier, extractor, cfg.Location),
+		handler.NewReminderReplyHandler(reminders, scheduling, classifier, cfg.Location),
+		handler.NewHelpHandler(),
+	)
 
 	// Initialize WhatsApp client
-	waClient, err := whatsapp.New(cfg, log.Logger)
+	waClient, err := whatsapp.New(cfg, log.Logger, msgHandler)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create WhatsApp client")
 	}
@@ -77,6 +125,25 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to connect to WhatsApp")
 	}
 
+	// Start reminder scheduler
+	runCtx, stop := context.WithCancel(ctx)
+	scheduler := reminder.NewScheduler(repos.appointments, repos.patients, repos.reminders, waClient,
+		reminderOffsets, time.Duration(cfg.ReminderInterval)*time.Second, cfg.Location)
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(runCtx)
+	}()
+
+	// Start purge of deleted records
+	purger := retention.NewPurger(repos.patients, repos.appointments,
+		time.Duration(cfg.RetentionDays)*24*time.Hour, time.Duration(cfg.PurgeInterval)*time.Second)
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		purger.Run(runCtx)
+	}()
+
 	// Log successful initialization
 	log.Info().Msg("Clara initialized successfully - ready to receive messages")
 
@@ -86,4 +153,9 @@ func main() {
 	<-sigCh
 
 	log.Info().Msg("Shutting down Clara...")
+
+	// Let in-flight reminder scan and purge finish before disconnecting
+	stop()
+	<-schedulerDone
+	<-purgerDone
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/repository/mongo"
+	"github.com/rs/zerolog/log"
+)
+
+// runMigrate implements `clara migrate [-dry-run] [-status] [-force-unlock]`
+// for the MongoDB backend. SQLite databases migrate when opened.
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list pending migrations without applying them")
+	status := flags.Bool("status", false, "list all migrations and when they were applied")
+	forceUnlock := flags.Bool("force-unlock", false, "remove migration lock left by a crashed instance")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.StorageBackend != config.StorageMongo {
+		return fmt.Errorf("migrate only applies to STORAGE_BACKEND=%s; SQLite migrates on startup", config.StorageMongo)
+	}
+
+	ctx := context.Background()
+	client, db, err := mongo.Connect(ctx, cfg.MongoURI, cfg.DBName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := mongo.Disconnect(context.Background(), client); err != nil {
+			log.Error().Err(err).Msg("Failed to disconnect from MongoDB")
+		}
+	}()
+
+	switch {
+	case *forceUnlock:
+		return mongo.ForceUnlock(ctx, db)
+
+	case *status:
+		statuses, err := mongo.MigrationStatuses(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if !s.AppliedAt.IsZero() {
+				applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(os.Stdout, "%3d  %-19s  %s\n", s.Version, applied, s.Description)
+		}
+		return nil
+	}
+
+	migrations, err := mongo.Migrate(ctx, db, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "applied"
+	if *dryRun {
+		verb = "pending"
+	}
+	if len(migrations) == 0 {
+		fmt.Fprintln(os.Stdout, "no migrations "+verb)
+	}
+	for _, m := range migrations {
+		fmt.Fprintf(os.Stdout, "%s %3d  %s\n", verb, m.Version, m.Description)
+	}
+	return nil
+}
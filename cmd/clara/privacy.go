@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/schedule"
+	"github.com/matheusmassa1/clara/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// runExport implements `clara export (-phone <number> | -id <patient id>) [-out <file>]`:
+// writes everything stored about a patient as JSON, to stdout by default.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	phone := flags.String("phone", "", "patient phone number")
+	id := flags.String("id", "", "patient ID, for patients already erased")
+	out := flags.String("out", "", "write export to file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return withPrivacy(func(ctx context.Context, patients *service.PatientService, privacy *service.PrivacyService) error {
+		patientID, err := lookupPatient(ctx, patients, *phone, *id)
+		if err != nil {
+			return err
+		}
+
+		export, err := privacy.Export(ctx, patientID)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(export); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		return nil
+	})
+}
+
+// runErase implements `clara erase (-phone <number> | -id <patient id>) -confirm`:
+// anonymizes a patient, keeping their appointments for statistics.
+func runErase(args []string) error {
+	flags := flag.NewFlagSet("erase", flag.ContinueOnError)
+	phone := flags.String("phone", "", "patient phone number")
+	id := flags.String("id", "", "patient ID")
+	confirm := flags.Bool("confirm", false, "confirm erasure, which cannot be undone")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !*confirm {
+		return errors.New("erasure cannot be undone, pass -confirm to proceed")
+	}
+
+	return withPrivacy(func(ctx context.Context, patients *service.PatientService, privacy *service.PrivacyService) error {
+		patientID, err := lookupPatient(ctx, patients, *phone, *id)
+		if err != nil {
+			return err
+		}
+		if err := privacy.Erase(ctx, patientID); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stdout, "erased patient %s\n", patientID.Hex())
+		return nil
+	})
+}
+
+// withPrivacy opens storage and runs fn as clinic staff.
+func withPrivacy(fn func(ctx context.Context, patients *service.PatientService, privacy *service.PrivacyService) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	ctx := context.Background()
+	repos, closeStorage, err := openRepositories(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	clinicSchedule, err := schedule.FromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load clinic schedule: %w", err)
+	}
+	scheduling := service.NewSchedulingService(repos.appointments, schedule.NewAvailability(clinicSchedule, repos.appointments))
+	patients := service.NewPatientService(repos.patients)
+	privacy := service.NewPrivacyService(repos.patients, repos.appointments, repos.reminders, repos.sessions, repos.audit, scheduling)
+
+	return fn(repository.WithActor(ctx, domain.ActorStaff), patients, privacy)
+}
+
+// lookupPatient resolves patient by phone or ID, including deleted patients.
+func lookupPatient(ctx context.Context, patients *service.PatientService, phone, id string) (primitive.ObjectID, error) {
+	switch {
+	case phone != "" && id != "":
+		return primitive.NilObjectID, errors.New("pass either -phone or -id, not both")
+	case id != "":
+		patientID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return primitive.NilObjectID, fmt.Errorf("invalid patient id %q: %w", id, err)
+		}
+		return patientID, nil
+	case phone != "":
+		patient, err := patients.GetByPhone(repository.WithDeleted(ctx), phone)
+		if err != nil {
+			return primitive.NilObjectID, fmt.Errorf("failed to find patient with phone %s: %w", phone, err)
+		}
+		return patient.ID, nil
+	}
+	return primitive.NilObjectID, errors.New("pass -phone or -id")
+}
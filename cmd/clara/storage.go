@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/audit"
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/mongo"
+	"github.com/matheusmassa1/clara/internal/repository/sqlite"
+	"github.com/rs/zerolog/log"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// repositories groups data access for the configured storage backend.
+// Patient and appointment mutations are recorded in the audit log.
+type repositories struct {
+	patients     repository.PatientRepository
+	appointments repository.AppointmentRepository
+	sessions     repository.SessionRepository
+	reminders    repository.ReminderRepository
+	audit        repository.AuditRepository
+}
+
+// openRepositories connects to STORAGE_BACKEND and prepares its schema.
+// Returned close func releases the connection.
+func openRepositories(ctx context.Context, cfg *config.Config) (*repositories, func(), error) {
+	sessionTTL := time.Duration(cfg.SessionTimeout) * time.Second
+
+	switch cfg.StorageBackend {
+	case config.StorageSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		closeFn := func() {
+			if err := sqlite.Close(db); err != nil {
+				log.Error().Err(err).Msg("Failed to close SQLite database")
+			}
+		}
+
+		return audited(&repositories{
+			patients:     sqlite.NewPatientRepository(db),
+			appointments: sqlite.NewAppointmentRepository(db),
+			sessions:     sqlite.NewSessionRepository(db, sessionTTL),
+			reminders:    sqlite.NewReminderRepository(db),
+			audit:        sqlite.NewAuditRepository(db),
+		}), closeFn, nil
+
+	case config.StorageMongo:
+		client, db, err := mongo.Connect(ctx, cfg.MongoURI, cfg.DBName)
+		if err != nil {
+			return nil, nil, err
+		}
+		closeFn := func() {
+			if err := mongo.Disconnect(context.Background(), client); err != nil {
+				log.Error().Err(err).Msg("Failed to disconnect from MongoDB")
+			}
+		}
+
+		if err := prepareMongo(ctx, db, cfg.MigrateOnStart); err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+
+		return audited(&repositories{
+			patients:     mongo.NewPatientRepository(db),
+			appointments: mongo.NewAppointmentRepository(db),
+			sessions:     mongo.NewSessionRepository(db, sessionTTL),
+			reminders:    mongo.NewReminderRepository(db),
+			audit:        mongo.NewAuditRepository(db),
+		}), closeFn, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+}
+
+// audited wraps patient and appointment repositories of repos to append
+// their mutations to the audit log.
+func audited(repos *repositories) *repositories {
+	repos.patients = audit.NewPatientRepository(repos.patients, repos.appointments, repos.audit)
+	repos.appointments = audit.NewAppointmentRepository(repos.appointments, repos.audit)
+	return repos
+}
+
+// prepareMongo applies pending migrations, or refuses to start with an
+// outdated schema when migrations are run separately.
+func prepareMongo(ctx context.Context, db *mongodriver.Database, migrate bool) error {
+	if migrate {
+		if _, err := mongo.Migrate(ctx, db, false); err != nil {
+			return fmt.Errorf("failed to migrate mongodb: %w", err)
+		}
+		return nil
+	}
+
+	pending, err := mongo.PendingMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending mongodb migrations, run `clara migrate`", len(pending))
+	}
+	return nil
+}
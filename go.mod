@@ -9,6 +9,7 @@ require (
 	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	go.mau.fi/whatsmeow v0.0.0-20251110110826-a121e2b9cd1e
 	go.mongodb.org/mongo-driver v1.17.6
+	golang.org/x/text v0.30.0
 	google.golang.org/protobuf v1.36.10
 )
 
@@ -36,5 +37,4 @@ require (
 	golang.org/x/net v0.46.0 // indirect
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/text v0.30.0 // indirect
 )
@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AppointmentRepository records appointment mutations of the wrapped
+// repository. Reads pass through unchanged.
+type AppointmentRepository struct {
+	repository.AppointmentRepository
+	audit recorder
+}
+
+// NewAppointmentRepository wraps repo, appending its mutations to entries.
+func NewAppointmentRepository(repo repository.AppointmentRepository, entries repository.AuditRepository) repository.AppointmentRepository {
+	return &AppointmentRepository{
+		AppointmentRepository: repo,
+		audit:                 newRecorder(entries, domain.EntityAppointment),
+	}
+}
+
+// Create inserts appointment and records it.
+func (r *AppointmentRepository) Create(ctx context.Context, apt *domain.Appointment) error {
+	if err := r.AppointmentRepository.Create(ctx, apt); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditCreate, apt.ID, nil, r.stored(ctx, apt.ID, apt), "")
+	return nil
+}
+
+// Update replaces appointment and records changed fields.
+func (r *AppointmentRepository) Update(ctx context.Context, apt *domain.Appointment) error {
+	before := snapshot(ctx, r.AppointmentRepository.GetByID, apt.ID)
+	if err := r.AppointmentRepository.Update(ctx, apt); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditUpdate, apt.ID, before, r.stored(ctx, apt.ID, apt), "")
+	return nil
+}
+
+// Reschedule swaps orig for next and, once both are stored, records both.
+func (r *AppointmentRepository) Reschedule(ctx context.Context, orig, next *domain.Appointment) error {
+	before := snapshot(ctx, r.AppointmentRepository.GetByID, orig.ID)
+	if err := r.AppointmentRepository.Reschedule(ctx, orig, next); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditCreate, next.ID, nil, r.stored(ctx, next.ID, next), "")
+	r.audit.record(ctx, domain.AuditUpdate, orig.ID, before, r.stored(ctx, orig.ID, orig), "")
+	return nil
+}
+
+// Delete soft-deletes appointment and records it.
+func (r *AppointmentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	before := snapshot(ctx, r.AppointmentRepository.GetByID, id)
+	if err := r.AppointmentRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditDelete, id, before, snapshot(ctx, r.AppointmentRepository.GetByID, id), "")
+	return nil
+}
+
+// Restore undeletes appointment and records it.
+func (r *AppointmentRepository) Restore(ctx context.Context, id primitive.ObjectID) error {
+	before := snapshot(ctx, r.AppointmentRepository.GetByID, id)
+	if err := r.AppointmentRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditRestore, id, before, snapshot(ctx, r.AppointmentRepository.GetByID, id), "")
+	return nil
+}
+
+// Purge removes appointments deleted before cutoff and records how many.
+func (r *AppointmentRepository) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	n, err := r.AppointmentRepository.Purge(ctx, cutoff)
+	if n > 0 {
+		note := fmt.Sprintf("%d appointments deleted before %s", n, cutoff.UTC().Format(time.RFC3339))
+		r.audit.record(ctx, domain.AuditPurge, primitive.NilObjectID, nil, nil, note)
+	}
+	return n, err
+}
+
+// stored returns appointment as stored, or apt if it cannot be read back.
+func (r *AppointmentRepository) stored(ctx context.Context, id primitive.ObjectID, apt *domain.Appointment) any {
+	if after := snapshot(ctx, r.AppointmentRepository.GetByID, id); after != nil {
+		return after
+	}
+	return apt
+}
@@ -0,0 +1,164 @@
+// Package audit records every patient and appointment mutation in the audit
+// log. Repositories are wrapped rather than changed, so each storage backend
+// is audited the same way.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// personalFields are patient fields holding personal data. The audit log is
+// append-only and outlives erasure, so their values are never recorded.
+var personalFields = []string{"name", "phone"}
+
+// recorder appends audit entries for one entity type.
+type recorder struct {
+	entries  repository.AuditRepository
+	entity   string
+	redacted []string // Fields recorded without values
+	now      func() time.Time
+}
+
+func newRecorder(entries repository.AuditRepository, entity string, redacted ...string) recorder {
+	return recorder{entries: entries, entity: entity, redacted: redacted, now: time.Now}
+}
+
+// record appends entry for mutation of entity id from before to after, by the
+// actor and correlation ID of ctx. The mutation already happened, so a failed
+// append is logged rather than returned.
+func (r recorder) record(ctx context.Context, action string, id primitive.ObjectID, before, after any, note string) {
+	r.append(ctx, action, id, r.changes(id, before, after), note)
+}
+
+// recordRedacted is record without field values, for changes whose values
+// must not be kept, such as erased personal data.
+func (r recorder) recordRedacted(ctx context.Context, action string, id primitive.ObjectID, before, after any, note string) {
+	changes := r.changes(id, before, after)
+	for i := range changes {
+		changes[i].Before, changes[i].After = "", ""
+	}
+	r.append(ctx, action, id, changes, note)
+}
+
+// changes returns changes from before to after, none if they cannot be
+// encoded. Values of redacted fields are left out.
+func (r recorder) changes(id primitive.ObjectID, before, after any) []domain.FieldChange {
+	changes, err := diff(before, after)
+	if err != nil {
+		log.Error().Err(err).Str("entity", r.entity).Str("entity_id", id.Hex()).Msg("failed to diff audited entity")
+	}
+	for i := range changes {
+		if slices.Contains(r.redacted, changes[i].Field) {
+			changes[i].Before, changes[i].After = "", ""
+		}
+	}
+	return changes
+}
+
+// append appends entry with changes, logging failure.
+func (r recorder) append(ctx context.Context, action string, id primitive.ObjectID, changes []domain.FieldChange, note string) {
+	entry := &domain.AuditEntry{
+		At:            r.now().UTC(),
+		Actor:         repository.ActorFrom(ctx),
+		CorrelationID: repository.CorrelationIDFrom(ctx),
+		Action:        action,
+		Entity:        r.entity,
+		EntityID:      id,
+		Changes:       changes,
+		Note:          note,
+	}
+	if err := r.entries.Append(context.WithoutCancel(ctx), entry); err != nil {
+		log.Error().
+			Err(err).
+			Str("action", action).
+			Str("entity", r.entity).
+			Str("entity_id", id.Hex()).
+			Msg("failed to append audit entry")
+	}
+}
+
+// snapshot returns stored state of entity for diffing, nil if it cannot be read.
+func snapshot[T any](ctx context.Context, get func(context.Context, primitive.ObjectID) (T, error), id primitive.ObjectID) any {
+	v, err := get(repository.WithDeleted(ctx), id)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Warn().Err(err).Str("entity_id", id.Hex()).Msg("failed to read audited entity")
+		}
+		return nil
+	}
+	return v
+}
+
+// diff lists JSON fields that differ between before and after, by field name.
+// Either may be nil, e.g. before a create.
+func diff(before, after any) ([]domain.FieldChange, error) {
+	from, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []domain.FieldChange
+	for _, name := range names {
+		if name == "id" || bytes.Equal(from[name], to[name]) {
+			continue
+		}
+		changes = append(changes, domain.FieldChange{
+			Field:  name,
+			Before: string(from[name]),
+			After:  string(to[name]),
+		})
+	}
+	return changes, nil
+}
+
+// zeroValues are JSON encodings of zero values that omitempty keeps, such as
+// time.Time and primitive.ObjectID. Fields holding them count as absent.
+var zeroValues = []string{`null`, `""`, `[]`, `{}`, `"0001-01-01T00:00:00Z"`, `"000000000000000000000000"`}
+
+// fields encodes v as JSON object fields without zero values, none if v is nil.
+func fields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for name, value := range m {
+		if slices.Contains(zeroValues, string(value)) {
+			delete(m, name)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// cascadeNote marks appointment entries caused by a patient delete or restore.
+const cascadeNote = "cascade"
+
+// PatientRepository records patient mutations of the wrapped repository,
+// including appointments deleted and restored along with the patient.
+// Name and phone changes are recorded without their values, so an erased
+// patient leaves no personal data in the log. Reads pass through unchanged.
+type PatientRepository struct {
+	repository.PatientRepository
+	appointments repository.AppointmentRepository
+	audit        recorder
+	cascade      recorder
+}
+
+// NewPatientRepository wraps repo, appending its mutations to entries.
+// Appointments are read to record the ones a patient delete or restore cascades to.
+func NewPatientRepository(
+	repo repository.PatientRepository,
+	appointments repository.AppointmentRepository,
+	entries repository.AuditRepository,
+) repository.PatientRepository {
+	return &PatientRepository{
+		PatientRepository: repo,
+		appointments:      appointments,
+		audit:             newRecorder(entries, domain.EntityPatient, personalFields...),
+		cascade:           newRecorder(entries, domain.EntityAppointment),
+	}
+}
+
+// Create inserts patient and records it.
+func (r *PatientRepository) Create(ctx context.Context, patient *domain.Patient) error {
+	if err := r.PatientRepository.Create(ctx, patient); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditCreate, patient.ID, nil, r.stored(ctx, patient.ID, patient), "")
+	return nil
+}
+
+// Update replaces patient and records changed fields.
+func (r *PatientRepository) Update(ctx context.Context, patient *domain.Patient) error {
+	before := snapshot(ctx, r.PatientRepository.GetByID, patient.ID)
+	if err := r.PatientRepository.Update(ctx, patient); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditUpdate, patient.ID, before, r.stored(ctx, patient.ID, patient), "")
+	return nil
+}
+
+// Delete soft-deletes patient and records it with each appointment deleted along.
+func (r *PatientRepository) Delete(ctx context.Context, id primitive.ObjectID, cascade bool) error {
+	before := snapshot(ctx, r.PatientRepository.GetByID, id)
+	apts := r.appointmentsOf(ctx, id)
+	if err := r.PatientRepository.Delete(ctx, id, cascade); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditDelete, id, before, snapshot(ctx, r.PatientRepository.GetByID, id), "")
+	r.recordCascade(ctx, domain.AuditDelete, apts, true)
+	return nil
+}
+
+// Restore undeletes patient and records it with each appointment restored along.
+func (r *PatientRepository) Restore(ctx context.Context, id primitive.ObjectID) error {
+	before := snapshot(ctx, r.PatientRepository.GetByID, id)
+	apts := r.appointmentsOf(repository.WithDeleted(ctx), id)
+	if err := r.PatientRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	r.audit.record(ctx, domain.AuditRestore, id, before, snapshot(ctx, r.PatientRepository.GetByID, id), "")
+	r.recordCascade(ctx, domain.AuditRestore, apts, false)
+	return nil
+}
+
+// Purge removes patients deleted before cutoff and records how many.
+func (r *PatientRepository) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	n, err := r.PatientRepository.Purge(ctx, cutoff)
+	if n > 0 {
+		note := fmt.Sprintf("%d patients deleted before %s, with their appointments", n, cutoff.UTC().Format(time.RFC3339))
+		r.audit.record(ctx, domain.AuditPurge, primitive.NilObjectID, nil, nil, note)
+	}
+	return n, err
+}
+
+// Erase anonymizes patient and records which fields were erased, leaving
+// their values out of the audit log.
+func (r *PatientRepository) Erase(ctx context.Context, id primitive.ObjectID) error {
+	before := snapshot(ctx, r.PatientRepository.GetByID, id)
+	if err := r.PatientRepository.Erase(ctx, id); err != nil {
+		return err
+	}
+
+	r.audit.recordRedacted(ctx, domain.AuditErase, id, before, snapshot(ctx, r.PatientRepository.GetByID, id), "")
+	return nil
+}
+
+// stored returns patient as stored, or patient if it cannot be read back.
+func (r *PatientRepository) stored(ctx context.Context, id primitive.ObjectID, patient *domain.Patient) any {
+	if after := snapshot(ctx, r.PatientRepository.GetByID, id); after != nil {
+		return after
+	}
+	return patient
+}
+
+// appointmentsOf returns appointments of patient visible through ctx.
+// Incomplete on error; cascaded changes to the rest go unrecorded.
+func (r *PatientRepository) appointmentsOf(ctx context.Context, patientID primitive.ObjectID) []*domain.Appointment {
+	list := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return r.appointments.ListByPatient(ctx, patientID, opts)
+	}
+
+	var apts []*domain.Appointment
+	for apt, err := range repository.StreamAppointments(ctx, repository.ListOptions{}, list) {
+		if err != nil {
+			log.Error().Err(err).Str("patient_id", patientID.Hex()).Msg("failed to list appointments for audit")
+			break
+		}
+		apts = append(apts, apt)
+	}
+	return apts
+}
+
+// recordCascade records action for each of apts the patient mutation moved
+// into the given deleted state.
+func (r *PatientRepository) recordCascade(ctx context.Context, action string, apts []*domain.Appointment, deleted bool) {
+	for _, before := range apts {
+		if before.IsDeleted() == deleted {
+			continue
+		}
+
+		after, err := r.appointments.GetByID(repository.WithDeleted(ctx), before.ID)
+		if err != nil {
+			log.Warn().Err(err).Str("appointment_id", before.ID.Hex()).Msg("failed to read audited appointment")
+			continue
+		}
+		if after.IsDeleted() == deleted {
+			r.cascade.record(ctx, action, before.ID, before, after, cascadeNote)
+		}
+	}
+}
@@ -3,19 +3,54 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// NLP engine options for NLP_ENGINE.
+const (
+	NLPEngineHF     = "hf"     // Hugging Face only
+	NLPEngineRules  = "rules"  // Offline keyword rules only
+	NLPEngineHybrid = "hybrid" // Hugging Face with rules fallback
+)
+
+// Storage backend options for STORAGE_BACKEND.
+const (
+	StorageMongo  = "mongo"  // MongoDB server at MONGO_URI
+	StorageSQLite = "sqlite" // Single SQLite file at SQLITE_PATH
+)
+
 // Config holds all application configuration.
 // Immutable after initialization.
 type Config struct {
+	StorageBackend      string
 	MongoURI            string
 	DBName              string
+	MigrateOnStart      bool
+	SQLitePath          string
 	LogLevel            string
 	HFAPIKey            string
 	HFIntentModel       string
 	HFNERModel          string
+	HFAPIURL            string
+	HFIntentLabels      string
+	HFTimeout           int
+	HFMaxRetries        int
+	NLPEngine           string
+	NLPMinConfidence    float64
+	ClinicTimezone      string
+	Location            *time.Location // Loaded from ClinicTimezone
+	ClinicHours         string
+	ClinicBreaks        string
+	ClinicClosures      string
+	SlotMinutes         int
+	SlotBufferMinutes   int
+	AppointmentTypes    string
+	ReminderOffsets     string
+	ReminderInterval    int
+	RetentionDays       int // Days before soft-deleted records are purged
+	PurgeInterval       int
 	SessionTimeout      int
 	SessionDir          string
 	WAMaxRetries        int
@@ -31,14 +66,34 @@ func Load() (*Config, error) {
 	_ = godotenv.Load()
 
 	cfg := &Config{
+		StorageBackend:      getEnv("STORAGE_BACKEND", StorageMongo),
 		MongoURI:            getEnv("MONGO_URI", ""),
 		DBName:              getEnv("DB_NAME", "clara"),
+		MigrateOnStart:      getEnvBool("MIGRATE_ON_START", true), // false: run `clara migrate` instead
+		SQLitePath:          getEnv("SQLITE_PATH", "data/clara.db"),
 		LogLevel:            getEnv("LOG_LEVEL", "info"),
 		HFAPIKey:            getEnv("HF_API_KEY", ""),
 		HFIntentModel:       getEnv("HF_INTENT_MODEL", "neuralmind/bert-base-portuguese-cased"),
 		HFNERModel:          getEnv("HF_NER_MODEL", "pierreguillou/ner-bert-base-cased-pt-lenerbr"),
-		SessionTimeout:      getEnvInt("SESSION_TIMEOUT", 900), // 15 min default
+		HFAPIURL:            getEnv("HF_API_URL", "https://router.huggingface.co/hf-inference/models"),
+		HFIntentLabels:      getEnv("HF_INTENT_LABELS", ""), // e.g. "LABEL_0=schedule,LABEL_1=cancel"
+		HFTimeout:           getEnvInt("HF_TIMEOUT", 10),    // seconds per request
+		HFMaxRetries:        getEnvInt("HF_MAX_RETRIES", 3),
+		NLPEngine:           getEnv("NLP_ENGINE", NLPEngineHybrid),
+		NLPMinConfidence:    getEnvFloat("NLP_MIN_CONFIDENCE", 0.6),
+		ClinicTimezone:      getEnv("CLINIC_TIMEZONE", "America/Sao_Paulo"),
+		ClinicHours:         getEnv("CLINIC_HOURS", "mon-fri 08:00-18:00"),
+		ClinicBreaks:        getEnv("CLINIC_BREAKS", "12:00-13:00"),
+		ClinicClosures:      getEnv("CLINIC_CLOSURES", ""), // e.g. "2026-12-25,2026-12-31..2027-01-02"
+		SlotMinutes:         getEnvInt("SLOT_MINUTES", 50),
+		SlotBufferMinutes:   getEnvInt("SLOT_BUFFER_MINUTES", 10),
+		AppointmentTypes:    getEnv("APPOINTMENT_TYPES", ""),      // e.g. "retorno:30,avaliacao:60"
+		ReminderOffsets:     getEnv("REMINDER_OFFSETS", "24h,2h"), // empty disables reminders
+		ReminderInterval:    getEnvInt("REMINDER_INTERVAL", 60),   // seconds between scans
+		SessionTimeout:      getEnvInt("SESSION_TIMEOUT", 900),    // 15 min default
 		SessionDir:          getEnv("SESSION_DIR", "tmp/whatsapp_session"),
+		RetentionDays:       getEnvInt("DELETED_RETENTION_DAYS", 90), // 0 keeps deleted records
+		PurgeInterval:       getEnvInt("PURGE_INTERVAL", 3600),       // seconds between purges
 		WAMaxRetries:        getEnvInt("WA_MAX_RETRIES", 5),
 		WABackoffMultiplier: getEnvFloat("WA_BACKOFF_MULTIPLIER", 2.0),
 		WAReplyOnError:      getEnvBool("WA_REPLY_ON_ERROR", true),
@@ -48,16 +103,37 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
+	// Already checked by validate
+	cfg.Location, _ = time.LoadLocation(cfg.ClinicTimezone)
+
 	return cfg, nil
 }
 
 // validate checks required fields are set.
 func (c *Config) validate() error {
-	if c.MongoURI == "" {
-		return fmt.Errorf("MONGO_URI is required")
+	switch c.StorageBackend {
+	case StorageMongo:
+		if c.MongoURI == "" {
+			return fmt.Errorf("MONGO_URI is required (or set STORAGE_BACKEND=sqlite)")
+		}
+	case StorageSQLite:
+		if c.SQLitePath == "" {
+			return fmt.Errorf("SQLITE_PATH is required")
+		}
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be one of %s, %s", StorageMongo, StorageSQLite)
+	}
+	switch c.NLPEngine {
+	case NLPEngineHF, NLPEngineRules, NLPEngineHybrid:
+	default:
+		return fmt.Errorf("NLP_ENGINE must be one of %s, %s, %s", NLPEngineHF, NLPEngineRules, NLPEngineHybrid)
+	}
+	if _, err := time.LoadLocation(c.ClinicTimezone); err != nil {
+		return fmt.Errorf("CLINIC_TIMEZONE is invalid: %w", err)
 	}
-	if c.HFAPIKey == "" {
-		return fmt.Errorf("HF_API_KEY is required")
+	// HF key only needed when remote model is used
+	if c.HFAPIKey == "" && c.NLPEngine != NLPEngineRules {
+		return fmt.Errorf("HF_API_KEY is required (or set NLP_ENGINE=rules)")
 	}
 	return nil
 }
@@ -9,23 +9,58 @@ import (
 
 // Appointment status constants
 const (
-	StatusPending   = "pending"
-	StatusConfirmed = "confirmed"
-	StatusCancelled = "cancelled"
+	StatusPending     = "pending"
+	StatusConfirmed   = "confirmed"
+	StatusCancelled   = "cancelled"
+	StatusCompleted   = "completed"
+	StatusNoShow      = "no_show"
+	StatusRescheduled = "rescheduled" // Replaced by another appointment
 )
 
+// DefaultAppointmentType is the regular session booked via WhatsApp
+const DefaultAppointmentType = "consulta"
+
+// DefaultAppointmentDuration is assumed for appointments stored without end time
+const DefaultAppointmentDuration = 50 * time.Minute
+
+// MaxAppointmentDuration bounds a single appointment
+const MaxAppointmentDuration = 8 * time.Hour
+
+// AgendaStep is agenda granularity; clinic hours and slot lengths are multiples of it
+const AgendaStep = 5 * time.Minute
+
 // Appointment represents an appointment entity
 type Appointment struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	DateTime time.Time          `bson:"datetime" json:"datetime"`
+	DateTime time.Time          `bson:"datetime" json:"datetime"`         // Start time
+	EndTime  time.Time          `bson:"end_datetime" json:"end_datetime"` // End time (exclusive)
+	Type     string             `bson:"type,omitempty" json:"type,omitempty"`
 	Patient  primitive.ObjectID `bson:"patient" json:"patient"` // Patient reference
 	Status   string             `bson:"status" json:"status"`
+
+	// Professional attending; empty when the clinic has a single professional
+	Professional string    `bson:"professional,omitempty" json:"professional,omitempty"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"` // Set on create if zero
+
+	// Reschedule lineage: set on the replacement and the replaced appointment
+	RescheduledFrom primitive.ObjectID `bson:"rescheduled_from,omitempty" json:"rescheduled_from,omitempty"`
+	RescheduledTo   primitive.ObjectID `bson:"rescheduled_to,omitempty" json:"rescheduled_to,omitempty"`
+
+	StatusHistory []StatusChange `bson:"status_history,omitempty" json:"status_history,omitempty"`
+
+	// Soft deletion; zero DeletedAt for live appointments
+	DeletedAt time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string    `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"` // Actor constant
 }
 
 // Validate checks Appointment fields
 func (a *Appointment) Validate() error {
-	if a.Status != StatusPending && a.Status != StatusConfirmed && a.Status != StatusCancelled {
-		return errors.New("invalid status: must be pending, confirmed, or cancelled")
+	if _, ok := transitions[a.Status]; !ok || a.Status == "" {
+		return errors.New("invalid status")
+	}
+
+	if n := len(a.StatusHistory); n > 0 && a.StatusHistory[n-1].To != a.Status {
+		return errors.New("status does not match status history")
 	}
 
 	if a.Patient.IsZero() {
@@ -36,5 +71,46 @@ func (a *Appointment) Validate() error {
 		return errors.New("datetime cannot be zero")
 	}
 
+	if a.EndTime.IsZero() {
+		return errors.New("end datetime cannot be zero")
+	}
+
+	if !a.EndTime.After(a.DateTime) {
+		return errors.New("end datetime must be after datetime")
+	}
+
+	if a.Duration() > MaxAppointmentDuration {
+		return errors.New("duration exceeds maximum")
+	}
+
 	return nil
 }
+
+// End returns when appointment finishes.
+// Falls back to DefaultAppointmentDuration for records stored without end time.
+func (a *Appointment) End() time.Time {
+	if a.EndTime.IsZero() {
+		return a.DateTime.Add(DefaultAppointmentDuration)
+	}
+	return a.EndTime
+}
+
+// Duration returns appointment length
+func (a *Appointment) Duration() time.Duration {
+	return a.End().Sub(a.DateTime)
+}
+
+// BlocksAgenda reports whether appointment occupies its time slot
+func (a *Appointment) BlocksAgenda() bool {
+	return a.Status != StatusCancelled && a.Status != StatusRescheduled
+}
+
+// IsDeleted reports whether appointment was soft-deleted
+func (a *Appointment) IsDeleted() bool {
+	return !a.DeletedAt.IsZero()
+}
+
+// IsOpen reports whether appointment still awaits attendance (pending or confirmed)
+func (a *Appointment) IsOpen() bool {
+	return a.Status == StatusPending || a.Status == StatusConfirmed
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit action constants
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"  // Soft delete
+	AuditRestore = "restore" // Undo of soft delete
+	AuditPurge   = "purge"   // Removal of soft-deleted records
+	AuditErase   = "erase"   // Anonymization on data subject request
+	AuditExport  = "export"  // Disclosure of data on data subject request
+)
+
+// Audited entity constants
+const (
+	EntityPatient     = "patient"
+	EntityAppointment = "appointment"
+)
+
+// AuditEntry records one mutation of a patient or appointment, or an export
+// of a patient's data.
+// Entries are append-only: never updated or removed.
+type AuditEntry struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	At            time.Time          `bson:"at" json:"at"`
+	Actor         string             `bson:"actor" json:"actor"`                                       // Who (see Actor constants)
+	CorrelationID string             `bson:"correlation_id,omitempty" json:"correlation_id,omitempty"` // Inbound message that caused it
+	Action        string             `bson:"action" json:"action"`
+	Entity        string             `bson:"entity" json:"entity"`
+	EntityID      primitive.ObjectID `bson:"entity_id,omitempty" json:"entity_id,omitempty"` // Zero for purges
+	Changes       []FieldChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	Note          string             `bson:"note,omitempty" json:"note,omitempty"` // e.g. "cascade"
+}
+
+// FieldChange is one field of an entity before and after a mutation.
+// Values are JSON-encoded; empty means the field was absent.
+type FieldChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before string `bson:"before,omitempty" json:"before,omitempty"`
+	After  string `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// Validate checks AuditEntry fields
+func (e *AuditEntry) Validate() error {
+	if e.At.IsZero() {
+		return errors.New("time cannot be zero")
+	}
+
+	if e.Actor == "" {
+		return errors.New("actor cannot be empty")
+	}
+
+	switch e.Action {
+	case AuditCreate, AuditUpdate, AuditDelete, AuditRestore, AuditPurge, AuditErase, AuditExport:
+	default:
+		return errors.New("invalid action")
+	}
+
+	switch e.Entity {
+	case EntityPatient, EntityAppointment:
+	default:
+		return errors.New("invalid entity")
+	}
+
+	if e.EntityID.IsZero() && e.Action != AuditPurge {
+		return errors.New("entity ID cannot be zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// PatientExport bundles everything stored about a patient, answering a data
+// subject access request (LGPD). Inbound messages are not stored, so the
+// conversation is the reminders sent and the dialog in progress, if any.
+type PatientExport struct {
+	ExportedAt   time.Time      `json:"exported_at"`
+	Patient      *Patient       `json:"patient"`
+	Appointments []*Appointment `json:"appointments"`      // Including deleted ones
+	Reminders    []*Reminder    `json:"reminders"`         // Reminders sent to patient
+	Session      *Session       `json:"session,omitempty"` // Dialog in progress
+	Audit        []*AuditEntry  `json:"audit"`             // Patient and appointment history, oldest first
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// Media kind constants
+const (
+	MediaImage    = "image"
+	MediaAudio    = "audio"
+	MediaVideo    = "video"
+	MediaDocument = "document"
+	MediaSticker  = "sticker"
+)
+
+// Message represents an inbound chat message, independent of transport
+type Message struct {
+	ID         string    `json:"id"`
+	Sender     string    `json:"sender"` // Sender phone (E.164) when known, otherwise transport address
+	Chat       string    `json:"chat"`   // Conversation address, opaque to handlers
+	Text       string    `json:"text"`
+	Quoted     *Quoted   `json:"quoted,omitempty"`
+	Media      *Media    `json:"media,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Quoted references the message being replied to
+type Quoted struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Media references an attachment kept by the transport
+type Media struct {
+	Kind     string `json:"kind"`
+	MimeType string `json:"mime_type"`
+	FileName string `json:"file_name,omitempty"`
+	Ref      string `json:"ref"` // Transport-specific locator (e.g. WhatsApp direct path)
+}
+
+// Reply represents an outbound chat message
+type Reply struct {
+	To       string    `json:"to"` // Empty means reply in the originating chat
+	Text     string    `json:"text"`
+	Document *Document `json:"document,omitempty"` // Attached file; Text becomes its caption
+}
+
+// Document is a file sent with a reply
+type Document struct {
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	Data     []byte `json:"-"`
+}
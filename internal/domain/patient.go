@@ -4,8 +4,13 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Patient represents a patient entity
@@ -13,8 +18,18 @@ type Patient struct {
 	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name  string             `bson:"name" json:"name"`
 	Phone string             `bson:"phone" json:"phone"` // WhatsApp number
+
+	// Soft deletion; zero DeletedAt for live patients
+	DeletedAt time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string    `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"` // Actor constant
+
+	// Erasure of personal data on request (LGPD); zero unless erased
+	ErasedAt time.Time `bson:"erased_at,omitempty" json:"erased_at,omitempty"`
 }
 
+// ErasedPhonePrefix starts the placeholder phone of erased patients
+const ErasedPhonePrefix = "erased:"
+
 var phoneRegex = regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
 
 // Validate checks Patient fields
@@ -39,3 +54,32 @@ func (p *Patient) Validate() error {
 
 	return nil
 }
+
+// IsDeleted reports whether patient was soft-deleted
+func (p *Patient) IsDeleted() bool {
+	return !p.DeletedAt.IsZero()
+}
+
+// IsErased reports whether patient's personal data was erased
+func (p *Patient) IsErased() bool {
+	return !p.ErasedAt.IsZero()
+}
+
+// Erase removes personal data of patient at given time. ID is kept so their
+// appointments still count in statistics; phone becomes a unique placeholder,
+// freeing the number for a new registration.
+func (p *Patient) Erase(at time.Time) {
+	p.Name = ""
+	p.Phone = ErasedPhonePrefix + p.ID.Hex()
+	p.ErasedAt = at
+}
+
+// NormalizeName folds name for search: lowercase, without diacritics, single
+// spaces ("  João  da Silva" → "joao da silva").
+func NormalizeName(name string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn))), name)
+	if err != nil {
+		folded = name
+	}
+	return strings.Join(strings.Fields(strings.ToLower(folded)), " ")
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reminder records a reminder sent (or being sent) for an appointment.
+// One reminder exists per appointment, offset and appointment start, so a
+// rescheduled appointment gets reminded again.
+type Reminder struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Appointment   primitive.ObjectID `bson:"appointment" json:"appointment"`       // Appointment reference
+	Patient       primitive.ObjectID `bson:"patient" json:"patient"`               // Patient reference
+	AppointmentAt time.Time          `bson:"appointment_at" json:"appointment_at"` // Appointment start when reminded
+	Offset        time.Duration      `bson:"offset" json:"offset"`                 // How long before start
+	MessageID     string             `bson:"message_id,omitempty" json:"message_id,omitempty"`
+	SentAt        time.Time          `bson:"sent_at,omitempty" json:"sent_at,omitempty"` // Zero while sending
+}
+
+// Validate checks Reminder fields
+func (r *Reminder) Validate() error {
+	if r.Appointment.IsZero() {
+		return errors.New("appointment ID cannot be zero")
+	}
+
+	if r.Patient.IsZero() {
+		return errors.New("patient ID cannot be zero")
+	}
+
+	if r.AppointmentAt.IsZero() {
+		return errors.New("appointment datetime cannot be zero")
+	}
+
+	if r.Offset <= 0 {
+		return errors.New("offset must be positive")
+	}
+
+	return nil
+}
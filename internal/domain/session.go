@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Session represents an ongoing conversation with a sender
+type Session struct {
+	ID           string            `bson:"_id" json:"id"`      // Sender address
+	Flow         string            `bson:"flow" json:"flow"`   // Active dialog (e.g. booking), empty if idle
+	Step         string            `bson:"step" json:"step"`   // Current step within flow
+	Slots        map[string]string `bson:"slots" json:"slots"` // Values collected so far
+	LastActivity time.Time         `bson:"last_activity" json:"last_activity"`
+	ExpiresAt    time.Time         `bson:"expires_at" json:"expires_at"`
+}
+
+// NewSession creates empty session for sender
+func NewSession(id string) *Session {
+	return &Session{ID: id, Slots: make(map[string]string)}
+}
+
+// Validate checks Session fields
+func (s *Session) Validate() error {
+	if strings.TrimSpace(s.ID) == "" {
+		return errors.New("session id cannot be empty")
+	}
+	return nil
+}
+
+// Expired reports whether session is past its expiry at now
+func (s *Session) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// Reset clears flow state, keeping session identity
+func (s *Session) Reset() {
+	s.Flow = ""
+	s.Step = ""
+	s.Slots = make(map[string]string)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Actor constants identify who triggered a status change
+const (
+	ActorPatient = "patient" // Patient via WhatsApp
+	ActorStaff   = "staff"   // Clinic staff
+	ActorSystem  = "system"  // Automated job
+)
+
+// ErrInvalidTransition is returned when status change is not allowed
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// transitions lists allowed next statuses per status.
+// Empty "from" is a new appointment. Cancelled, completed, no_show and
+// rescheduled are final.
+var transitions = map[string][]string{
+	"":                {StatusPending, StatusConfirmed},
+	StatusPending:     {StatusConfirmed, StatusCancelled, StatusRescheduled, StatusCompleted, StatusNoShow},
+	StatusConfirmed:   {StatusCancelled, StatusRescheduled, StatusCompleted, StatusNoShow},
+	StatusCancelled:   {},
+	StatusCompleted:   {},
+	StatusNoShow:      {},
+	StatusRescheduled: {},
+}
+
+// StatusChange records one appointment status transition
+type StatusChange struct {
+	From   string    `bson:"from" json:"from"` // Empty for creation
+	To     string    `bson:"to" json:"to"`
+	At     time.Time `bson:"at" json:"at"`
+	Actor  string    `bson:"actor" json:"actor"`                       // Who triggered it (see Actor constants)
+	Reason string    `bson:"reason,omitempty" json:"reason,omitempty"` // What triggered it (e.g. "reminder reply")
+}
+
+// TransitionError describes a rejected status change
+type TransitionError struct {
+	From string
+	To   string
+}
+
+// Error implements error
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition from %q to %q", e.From, e.To)
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidTransition)
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// CheckTransition returns *TransitionError unless from → to is allowed
+func CheckTransition(from, to string) error {
+	for _, next := range transitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return &TransitionError{From: from, To: to}
+}
+
+// Transition moves appointment to status, recording the change in history
+func (a *Appointment) Transition(to, actor, reason string, at time.Time) error {
+	if err := CheckTransition(a.Status, to); err != nil {
+		return err
+	}
+
+	a.StatusHistory = append(a.StatusHistory, StatusChange{
+		From:   a.Status,
+		To:     to,
+		At:     at,
+		Actor:  actor,
+		Reason: reason,
+	})
+	a.Status = to
+	return nil
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// allStatuses are every status, plus "" for a new appointment.
+var allStatuses = []string{
+	"", StatusPending, StatusConfirmed, StatusCancelled, StatusCompleted, StatusNoShow, StatusRescheduled,
+}
+
+func TestCheckTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{"", StatusPending}:                  true,
+		{"", StatusConfirmed}:                true,
+		{StatusPending, StatusConfirmed}:     true,
+		{StatusPending, StatusCancelled}:     true,
+		{StatusPending, StatusRescheduled}:   true,
+		{StatusPending, StatusCompleted}:     true,
+		{StatusPending, StatusNoShow}:        true,
+		{StatusConfirmed, StatusCancelled}:   true,
+		{StatusConfirmed, StatusRescheduled}: true,
+		{StatusConfirmed, StatusCompleted}:   true,
+		{StatusConfirmed, StatusNoShow}:      true,
+	}
+
+	// Every pair, including to itself and to an unknown status
+	statuses := append(allStatuses, "archived")
+	for _, from := range statuses {
+		for _, to := range statuses {
+			err := CheckTransition(from, to)
+			if allowed[[2]string{from, to}] {
+				if err != nil {
+					t.Errorf("CheckTransition(%q, %q) = %v, want allowed", from, to, err)
+				}
+				continue
+			}
+
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("CheckTransition(%q, %q) = %v, want %v", from, to, err, ErrInvalidTransition)
+				continue
+			}
+			var te *TransitionError
+			if !errors.As(err, &te) || te.From != from || te.To != to {
+				t.Errorf("CheckTransition(%q, %q) = %#v, want *TransitionError from %q to %q", from, to, err, from, to)
+			}
+		}
+	}
+}
+
+func TestTransitionErrorWrapped(t *testing.T) {
+	err := error(&TransitionError{From: StatusCancelled, To: StatusConfirmed})
+	if want := `invalid status transition from "cancelled" to "confirmed"`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	wrapped := fmt.Errorf("failed to update appointment: %w", err)
+	var te *TransitionError
+	if !errors.Is(wrapped, ErrInvalidTransition) || !errors.As(wrapped, &te) || te.To != StatusConfirmed {
+		t.Errorf("wrapped TransitionError not matched: %v", wrapped)
+	}
+}
+
+func TestTransitionRecordsHistory(t *testing.T) {
+	booked := time.Date(2030, 1, 6, 9, 0, 0, 0, time.UTC)
+	confirmed := booked.Add(20 * time.Hour)
+	apt := &Appointment{}
+
+	if err := apt.Transition(StatusPending, ActorPatient, "", booked); err != nil {
+		t.Fatal(err)
+	}
+	if err := apt.Transition(StatusConfirmed, ActorPatient, "reminder reply", confirmed); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []StatusChange{
+		{From: "", To: StatusPending, At: booked, Actor: ActorPatient},
+		{From: StatusPending, To: StatusConfirmed, At: confirmed, Actor: ActorPatient, Reason: "reminder reply"},
+	}
+	if apt.Status != StatusConfirmed {
+		t.Errorf("Status = %q, want %q", apt.Status, StatusConfirmed)
+	}
+	if len(apt.StatusHistory) != len(want) {
+		t.Fatalf("StatusHistory has %d changes, want %d", len(apt.StatusHistory), len(want))
+	}
+	for i, change := range apt.StatusHistory {
+		if change != want[i] {
+			t.Errorf("StatusHistory[%d] = %+v, want %+v", i, change, want[i])
+		}
+	}
+
+	// Rejected change leaves appointment untouched
+	err := apt.Transition(StatusPending, ActorStaff, "", confirmed.Add(time.Hour))
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("Transition back to pending: got %v, want %v", err, ErrInvalidTransition)
+	}
+	if apt.Status != StatusConfirmed || len(apt.StatusHistory) != len(want) {
+		t.Errorf("after rejected transition: status %q with %d changes, want %q with %d",
+			apt.Status, len(apt.StatusHistory), StatusConfirmed, len(want))
+	}
+}
+
+func TestValidateStatusHistory(t *testing.T) {
+	start := time.Date(2030, 1, 7, 14, 0, 0, 0, time.UTC)
+	newApt := func() *Appointment {
+		apt := &Appointment{DateTime: start, EndTime: start.Add(50 * time.Minute), Patient: primitive.NewObjectID()}
+		_ = apt.Transition(StatusPending, ActorStaff, "", start.Add(-time.Hour))
+		return apt
+	}
+
+	if err := newApt().Validate(); err != nil {
+		t.Fatalf("Validate(valid) = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(apt *Appointment)
+	}{
+		{"status changed without history", func(apt *Appointment) { apt.Status = StatusConfirmed }},
+		{"history ahead of status", func(apt *Appointment) {
+			apt.StatusHistory = append(apt.StatusHistory, StatusChange{From: StatusPending, To: StatusCancelled})
+		}},
+		{"unknown status", func(apt *Appointment) { apt.Status, apt.StatusHistory = "archived", nil }},
+		{"empty status", func(apt *Appointment) { apt.Status, apt.StatusHistory = "", nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apt := newApt()
+			tt.modify(apt)
+			if err := apt.Validate(); err == nil {
+				t.Error("Validate: expected error")
+			}
+		})
+	}
+
+	// Records stored before history was kept have none
+	legacy := newApt()
+	legacy.Status, legacy.StatusHistory = StatusConfirmed, nil
+	if err := legacy.Validate(); err != nil {
+		t.Errorf("Validate(without history) = %v", err)
+	}
+}
@@ -0,0 +1,449 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/service"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Booking flow steps:
+//
+//	(schedule intent) → ask_name    (unknown patient)
+//	(schedule intent) → ask_date    (known patient)
+//	ask_name          → ask_date    (patient registered)
+//	ask_date          → choose_slot (day has free slots)
+//	ask_date          → confirm     (requested exact time is free)
+//	choose_slot       → confirm     (slot picked)
+//	confirm           → done        ("sim": appointment created as pending)
+//	confirm           → ask_date    ("não")
+//
+// The reschedule flow reuses the date, slot and confirm steps:
+//
+//	(reschedule intent) → choose_appointment (several upcoming appointments)
+//	(reschedule intent) → ask_date           (one upcoming appointment)
+//	choose_appointment  → ask_date
+//	confirm             → done               ("sim": appointment rescheduled)
+//
+// Invalid input re-prompts the same step. "cancelar" at any step ends
+// the flow without booking.
+const (
+	flowBooking    = "booking"
+	flowReschedule = "reschedule"
+
+	stepAskName           = "ask_name"
+	stepChooseAppointment = "choose_appointment"
+	stepAskDate           = "ask_date"
+	stepChooseSlot        = "choose_slot"
+	stepConfirm           = "confirm"
+)
+
+// Session slot keys used by the booking flow.
+const (
+	slotPatientID = "patient_id"
+	slotDate      = "date"    // YYYY-MM-DD
+	slotOffered   = "offered" // Comma-separated RFC3339 start times
+	slotChosen    = "slot"    // RFC3339 start time
+
+	slotAppointmentID = "appointment_id" // Appointment being rescheduled
+	slotCandidates    = "candidates"     // Comma-separated appointment IDs to choose from
+	slotMinutes       = "minutes"        // Duration of appointment being rescheduled
+	slotRequest       = "request"        // Original reschedule message
+)
+
+// maxOfferedSlots caps how many times are listed to the patient.
+const maxOfferedSlots = 8
+
+// bookingStep handles message at one step, updating session in place.
+type bookingStep func(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error)
+
+// BookingHandler drives the multi-turn appointment booking dialog.
+// Passes messages through (no replies) unless a booking is in progress
+// or the message has schedule intent.
+type BookingHandler struct {
+	patients   *service.PatientService
+	scheduling *service.SchedulingService
+	sessions   repository.SessionRepository
+	classifier nlp.IntentClassifier
+	extractor  nlp.EntityExtractor
+	loc        *time.Location
+	now        func() time.Time
+	steps      map[string]bookingStep
+}
+
+// NewBookingHandler creates booking dialog handler.
+func NewBookingHandler(
+	patients *service.PatientService,
+	scheduling *service.SchedulingService,
+	sessions repository.SessionRepository,
+	classifier nlp.IntentClassifier,
+	extractor nlp.EntityExtractor,
+	loc *time.Location,
+) *BookingHandler {
+	h := &BookingHandler{
+		patients:   patients,
+		scheduling: scheduling,
+		sessions:   sessions,
+		classifier: classifier,
+		extractor:  extractor,
+		loc:        loc,
+		now:        time.Now,
+	}
+	h.steps = map[string]bookingStep{
+		stepAskName:           h.handleName,
+		stepChooseAppointment: h.handleAppointmentChoice,
+		stepAskDate:           h.handleDate,
+		stepChooseSlot:        h.handleSlotChoice,
+		stepConfirm:           h.handleConfirm,
+	}
+	return h
+}
+
+// Handle advances booking flow for sender.
+func (h *BookingHandler) Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	session, err := h.sessions.Get(ctx, msg.Sender)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load session: %w", err)
+		}
+		session = domain.NewSession(msg.Sender)
+	}
+
+	var replies []domain.Reply
+	if session.Flow == flowBooking || session.Flow == flowReschedule {
+		if isEscape(msg.Text) {
+			if session.Flow == flowReschedule {
+				return h.finish(ctx, session, reply("Remarcação cancelada. Sua consulta continua no horário original."))
+			}
+			return h.finish(ctx, session, reply("Agendamento cancelado. Se precisar, é só chamar!"))
+		}
+
+		step, ok := h.steps[session.Step]
+		if !ok {
+			log.Warn().Str("step", session.Step).Msg("unknown booking step, restarting flow")
+			session.Reset()
+			return h.finish(ctx, session, nil)
+		}
+
+		replies, err = step(ctx, session, msg)
+	} else {
+		if session.Flow != "" {
+			return nil, nil // Another dialog owns this conversation
+		}
+
+		result, cerr := h.classifier.Classify(ctx, msg.Text)
+		if cerr != nil {
+			return nil, fmt.Errorf("failed to classify message: %w", cerr)
+		}
+		switch result.Intent {
+		case nlp.IntentSchedule:
+			replies, err = h.start(ctx, session, msg)
+		case nlp.IntentReschedule:
+			replies, err = h.startReschedule(ctx, session, msg)
+		default:
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Flow == "" {
+		return h.finish(ctx, session, replies)
+	}
+	if err := h.sessions.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	return replies, nil
+}
+
+// start enters booking flow: identify patient, then ask for name or date.
+func (h *BookingHandler) start(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	s.Reset()
+	s.Flow = flowBooking
+
+	patient, err := h.patients.GetByPhone(ctx, msg.Sender)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get patient: %w", err)
+		}
+		s.Step = stepAskName
+		return reply("Olá! Vou te ajudar a agendar uma consulta. Qual é o seu nome completo?"), nil
+	}
+
+	s.Slots[slotPatientID] = patient.ID.Hex()
+	s.Step = stepAskDate
+
+	// Message may already carry a date ("quero marcar amanhã às 14h")
+	if _, err := nlp.ParseDateTime(msg.Text, h.now(), h.loc); err == nil {
+		return h.handleDate(ctx, s, msg)
+	}
+
+	return reply("Olá, %s! Para qual dia você gostaria de agendar? (ex.: amanhã, sexta, 20/10)", firstName(patient.Name)), nil
+}
+
+// handleName registers new patient with given name.
+func (h *BookingHandler) handleName(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	name := h.extractName(ctx, msg.Text)
+	if !looksLikeName(name) {
+		return reply("Não entendi. Por favor, informe seu nome completo (ou \"cancelar\" para sair)."), nil
+	}
+
+	patient, err := h.patients.Register(ctx, name, msg.Sender)
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		s.Reset()
+		return reply("Não consegui identificar seu número de telefone para o cadastro. Por favor, entre em contato com a clínica."), nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to register patient: %w", err)
+	}
+
+	s.Slots[slotPatientID] = patient.ID.Hex()
+	s.Step = stepAskDate
+	return reply("Obrigada, %s! Para qual dia você gostaria de agendar? (ex.: amanhã, sexta, 20/10)", firstName(patient.Name)), nil
+}
+
+// extractName returns the person named in text ("meu nome é Ana Souza"), or
+// else the whole text: most patients just send their name.
+func (h *BookingHandler) extractName(ctx context.Context, text string) string {
+	entities, err := h.extractor.Extract(ctx, text)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to extract name, using whole message")
+	}
+	for _, ent := range entities {
+		if ent.Type == nlp.EntityPerson {
+			return strings.Join(strings.Fields(ent.Text), " ")
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// handleDate parses requested day (and optional time), then offers free slots.
+func (h *BookingHandler) handleDate(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	now := h.now().In(h.loc)
+
+	dt, err := nlp.ParseDateTime(msg.Text, now, h.loc)
+	if err != nil {
+		if errors.Is(err, nlp.ErrNoDateTime) || errors.Is(err, nlp.ErrInvalidDateTime) {
+			return reply("Não consegui entender a data. Pode informar o dia? (ex.: amanhã, sexta, 20/10)"), nil
+		}
+		return nil, err
+	}
+
+	day := midnight(dt.Time)
+	if day.Before(midnight(now)) || (dt.HasTime && dt.Time.Before(now)) {
+		return reply("Essa data já passou. Para qual outro dia você gostaria de agendar?"), nil
+	}
+
+	slots, err := h.scheduling.FreeSlots(ctx, day, day.AddDate(0, 0, 1), h.duration(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find free slots: %w", err)
+	}
+	free := make([]time.Time, len(slots))
+	for i, slot := range slots {
+		free[i] = slot.Start
+	}
+	if len(free) == 0 {
+		return reply("Não há horários disponíveis em %s. Gostaria de tentar outro dia?", formatDay(day)), nil
+	}
+
+	s.Slots[slotDate] = day.Format("2006-01-02")
+
+	// Exact time requested and free: skip straight to confirmation
+	if dt.HasTime && !dt.Has(nlp.AmbiguityMeridiem) {
+		for _, slot := range free {
+			if slot.Equal(dt.Time) {
+				return h.askConfirm(s, slot), nil
+			}
+		}
+	}
+
+	if len(free) > maxOfferedSlots {
+		free = free[:maxOfferedSlots]
+	}
+
+	offered := make([]string, len(free))
+	labels := make([]string, len(free))
+	for i, slot := range free {
+		offered[i] = slot.Format(time.RFC3339)
+		labels[i] = formatClock(slot)
+	}
+	s.Slots[slotOffered] = strings.Join(offered, ",")
+	s.Step = stepChooseSlot
+
+	intro := fmt.Sprintf("Horários disponíveis em %s:", formatDay(day))
+	if dt.HasTime {
+		intro = fmt.Sprintf("O horário das %s não está disponível. Horários livres em %s:", formatClock(dt.Time), formatDay(day))
+	}
+	return reply("%s\n%s\nResponda com o número do horário desejado.", intro, numberedList(labels)), nil
+}
+
+// handleSlotChoice accepts option number or a listed time.
+func (h *BookingHandler) handleSlotChoice(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	offered, err := parseOffered(s.Slots[slotOffered], h.loc)
+	if err != nil || len(offered) == 0 {
+		s.Step = stepAskDate
+		return reply("Vamos recomeçar a escolha. Para qual dia você gostaria de agendar?"), nil
+	}
+
+	text := strings.TrimSpace(msg.Text)
+	if n, err := strconv.Atoi(strings.Trim(text, ").")); err == nil {
+		if n >= 1 && n <= len(offered) {
+			return h.askConfirm(s, offered[n-1]), nil
+		}
+		// Not an option number; maybe an hour ("9")
+		for _, slot := range offered {
+			if slot.Hour() == n && slot.Minute() == 0 {
+				return h.askConfirm(s, slot), nil
+			}
+		}
+	}
+
+	if dt, err := nlp.ParseDateTime(text, h.now(), h.loc); err == nil {
+		// Patient asked for another day instead of picking a slot
+		if dt.HasDate && dt.Time.Format("2006-01-02") != s.Slots[slotDate] {
+			return h.handleDate(ctx, s, msg)
+		}
+		if dt.HasTime {
+			for _, slot := range offered {
+				if slot.Hour() == dt.Time.Hour() && slot.Minute() == dt.Time.Minute() {
+					return h.askConfirm(s, slot), nil
+				}
+			}
+		}
+	}
+
+	labels := make([]string, len(offered))
+	for i, slot := range offered {
+		labels[i] = formatClock(slot)
+	}
+	return reply("Não encontrei essa opção. Escolha um dos horários:\n%s\n(ou \"cancelar\" para sair)", numberedList(labels)), nil
+}
+
+// handleConfirm books on "sim", goes back to date choice on "não".
+func (h *BookingHandler) handleConfirm(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	switch {
+	case isYes(msg.Text):
+	case isNo(msg.Text):
+		delete(s.Slots, slotChosen)
+		delete(s.Slots, slotOffered)
+		s.Step = stepAskDate
+		return reply("Tudo bem. Para qual outro dia você gostaria de agendar?"), nil
+	default:
+		return reply("Por favor, responda \"sim\" para confirmar ou \"não\" para escolher outro horário."), nil
+	}
+
+	start, err := time.ParseInLocation(time.RFC3339, s.Slots[slotChosen], h.loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slot in session: %w", err)
+	}
+	if s.Flow == flowReschedule {
+		return h.confirmReschedule(ctx, s, start)
+	}
+
+	patientID, err := primitive.ObjectIDFromHex(s.Slots[slotPatientID])
+	if err != nil {
+		return nil, fmt.Errorf("invalid patient id in session: %w", err)
+	}
+
+	apt, err := h.scheduling.Book(ctx, patientID, start, domain.DefaultAppointmentType, domain.ActorPatient, "booked via whatsapp")
+	switch {
+	case errors.Is(err, service.ErrSlotUnavailable), errors.Is(err, service.ErrInPast):
+		// Someone else took the slot while this patient was deciding
+		delete(s.Slots, slotChosen)
+		delete(s.Slots, slotOffered)
+		s.Step = stepAskDate
+		return reply("Que pena, esse horário acabou de ser ocupado. Para qual dia você gostaria de agendar?"), nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to book appointment: %w", err)
+	}
+
+	s.Reset()
+	return reply("Pronto! Sua consulta foi agendada para %s, %s. Você receberá a confirmação em breve.",
+		formatDay(start), formatRange(apt.DateTime, apt.EndTime)), nil
+}
+
+// askConfirm stores chosen slot and asks for confirmation.
+func (h *BookingHandler) askConfirm(s *domain.Session, slot time.Time) []domain.Reply {
+	s.Slots[slotChosen] = slot.Format(time.RFC3339)
+	s.Step = stepConfirm
+	end := slot.Add(h.duration(s))
+
+	if s.Flow == flowReschedule {
+		return reply("Confirma a remarcação para %s, %s? (sim/não)", formatDay(slot), formatRange(slot, end))
+	}
+	return reply("Confirma a consulta em %s, %s? (sim/não)", formatDay(slot), formatRange(slot, end))
+}
+
+// duration returns length of appointment being booked or rescheduled.
+func (h *BookingHandler) duration(s *domain.Session) time.Duration {
+	if minutes, err := strconv.Atoi(s.Slots[slotMinutes]); err == nil {
+		return time.Duration(minutes) * time.Minute
+	}
+	return h.scheduling.Duration(domain.DefaultAppointmentType)
+}
+
+// finish ends conversation state and returns replies.
+func (h *BookingHandler) finish(ctx context.Context, s *domain.Session, replies []domain.Reply) ([]domain.Reply, error) {
+	if err := h.sessions.Delete(ctx, s.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to delete session: %w", err)
+	}
+	return replies, nil
+}
+
+// parseOffered decodes offered slot list from session.
+func parseOffered(value string, loc *time.Location) ([]time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	slots := make([]time.Time, 0, len(parts))
+	for _, p := range parts {
+		t, err := time.ParseInLocation(time.RFC3339, p, loc)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, t.In(loc))
+	}
+	return slots, nil
+}
+
+// looksLikeName checks text is letters only with at least 2 characters.
+func looksLikeName(s string) bool {
+	letters := 0
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case r == ' ' || r == '\'' || r == '-' || r == '.':
+		default:
+			return false
+		}
+	}
+	return letters >= 2
+}
+
+// firstName returns first word of name.
+func firstName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return name
+}
+
+// midnight returns start of t's day in t's location.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
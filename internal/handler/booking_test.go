@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/memory"
+	"github.com/matheusmassa1/clara/internal/schedule"
+	"github.com/matheusmassa1/clara/internal/service"
+)
+
+const testSender = "+5511988887777"
+
+// bookingTest drives BookingHandler over memory repositories for one sender.
+type bookingTest struct {
+	t            *testing.T
+	handler      *BookingHandler
+	patients     repository.PatientRepository
+	appointments repository.AppointmentRepository
+	sessions     repository.SessionRepository
+	monday       time.Time // A Monday at least a week ahead
+	day          string    // monday as the patient writes it ("20/10")
+}
+
+// testLocation is the clinic zone in handler tests.
+var testLocation = time.FixedZone("BRT", -3*60*60)
+
+// newTestScheduling returns scheduling over appointments with mon-fri
+// 08:00-18:00 hours, lunch at 12:00, 50 minute slots and a 10 minute buffer.
+func newTestScheduling(t *testing.T, appointments repository.AppointmentRepository) *service.SchedulingService {
+	t.Helper()
+	hours, err := schedule.ParseHours("mon-fri 08:00-18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clinic := &schedule.Schedule{
+		Hours:      hours,
+		Breaks:     []schedule.Interval{{Start: 12 * 60, End: 13 * 60}},
+		SlotLength: 50 * time.Minute,
+		Buffer:     10 * time.Minute,
+		Durations:  map[string]time.Duration{domain.DefaultAppointmentType: 50 * time.Minute},
+		Location:   testLocation,
+	}
+	return service.NewSchedulingService(appointments, schedule.NewAvailability(clinic, appointments))
+}
+
+// newBookingTest sets up the newTestScheduling clinic; sessions expire after ttl.
+func newBookingTest(t *testing.T, ttl time.Duration) *bookingTest {
+	t.Helper()
+	loc := testLocation
+	y, m, d := time.Now().In(loc).AddDate(0, 0, 7).Date()
+	monday := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+
+	appointments := memory.NewAppointmentRepository()
+	patients := memory.NewPatientRepository(appointments)
+	sessions := memory.NewSessionRepository(ttl)
+	handler := NewBookingHandler(service.NewPatientService(patients), newTestScheduling(t, appointments), sessions,
+		nlp.NewRuleClassifier(), nlp.NewRuleExtractor(), loc)
+
+	return &bookingTest{
+		t:            t,
+		handler:      handler,
+		patients:     patients,
+		appointments: appointments,
+		sessions:     sessions,
+		monday:       monday,
+		day:          monday.Format("02/01"),
+	}
+}
+
+// send delivers text from the test sender and returns the replies joined.
+func (bt *bookingTest) send(text string) string {
+	bt.t.Helper()
+	replies, err := bt.handler.Handle(context.Background(), &domain.Message{ID: "msg", Sender: testSender, Text: text})
+	if err != nil {
+		bt.t.Fatalf("Handle(%q): %v", text, err)
+	}
+	texts := make([]string, len(replies))
+	for i, r := range replies {
+		texts[i] = r.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// expect sends text and checks the reply contains want and the flow is at step.
+func (bt *bookingTest) expect(text, want, step string) {
+	bt.t.Helper()
+	got := bt.send(text)
+	if !strings.Contains(got, want) {
+		bt.t.Errorf("reply to %q: got %q, want it to contain %q", text, got, want)
+	}
+	if current := bt.step(); current != step {
+		bt.t.Errorf("step after %q: got %q, want %q", text, current, step)
+	}
+}
+
+// step returns current booking step, or "" without a session.
+func (bt *bookingTest) step() string {
+	bt.t.Helper()
+	s, err := bt.sessions.Get(context.Background(), testSender)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ""
+	}
+	if err != nil {
+		bt.t.Fatal(err)
+	}
+	return s.Step
+}
+
+// register creates the test sender as a patient.
+func (bt *bookingTest) register(name string) *domain.Patient {
+	bt.t.Helper()
+	patient, err := service.NewPatientService(bt.patients).Register(context.Background(), name, testSender)
+	if err != nil {
+		bt.t.Fatal(err)
+	}
+	return patient
+}
+
+// booked returns appointments in the test week.
+func (bt *bookingTest) booked() []*domain.Appointment {
+	bt.t.Helper()
+	page, err := bt.appointments.ListByDateRange(context.Background(), bt.monday, bt.monday.AddDate(0, 0, 7), repository.ListOptions{})
+	if err != nil {
+		bt.t.Fatal(err)
+	}
+	return page.Appointments
+}
+
+func (bt *bookingTest) at(hour int) time.Time {
+	return bt.monday.Add(time.Duration(hour) * time.Hour)
+}
+
+func TestBookingNewPatient(t *testing.T) {
+	bt := newBookingTest(t, time.Hour)
+
+	bt.expect("Oi, quero agendar uma consulta", "Qual é o seu nome completo?", stepAskName)
+	bt.expect("Maria da Silva", "Obrigada, Maria! Para qual dia", stepAskDate)
+	patient, err := bt.patients.GetByPhone(context.Background(), testSender)
+	if err != nil {
+		t.Fatalf("patient not registered: %v", err)
+	}
+	if patient.Name != "Maria da Silva" {
+		t.Errorf("patient name: got %q, want %q", patient.Name, "Maria da Silva")
+	}
+
+	bt.expect(bt.day, "Horários disponíveis em seg "+bt.day+":\n1) 08:00\n2) 09:00", stepChooseSlot)
+	bt.expect("2", "Confirma a consulta em seg "+bt.day+", 09:00–09:50? (sim/não)", stepConfirm)
+	bt.expect("sim", "Pronto! Sua consulta foi agendada para seg "+bt.day+", 09:00–09:50.", "")
+
+	booked := bt.booked()
+	if len(booked) != 1 {
+		t.Fatalf("booked: got %d appointments, want 1", len(booked))
+	}
+	apt := booked[0]
+	if apt.Patient != patient.ID || !apt.DateTime.Equal(bt.at(9)) || apt.Status != domain.StatusPending {
+		t.Errorf("booked: got %s at %s for %s, want pending at %s for %s",
+			apt.Status, apt.DateTime, apt.Patient.Hex(), bt.at(9), patient.ID.Hex())
+	}
+}
+
+// failingExtractor fails every extraction.
+type failingExtractor struct{}
+
+func (failingExtractor) Extract(context.Context, string) ([]nlp.Entity, error) {
+	return nil, errors.New("ner unavailable")
+}
+
+func TestBookingExtractsName(t *testing.T) {
+	tests := []struct {
+		text      string
+		extractor nlp.EntityExtractor
+		want      string
+	}{
+		{"Meu nome é Maria da Silva", nlp.NewRuleExtractor(), "Maria da Silva"},
+		{"oi, me chamo João Souza", nlp.NewRuleExtractor(), "João Souza"},
+		{"  Ana   Lima ", nlp.NewRuleExtractor(), "Ana Lima"},
+		// Whole message is the name when extraction fails
+		{"Carla Dias", failingExtractor{}, "Carla Dias"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			bt := newBookingTest(t, time.Hour)
+			bt.handler.extractor = tt.extractor
+			bt.expect("quero agendar", "nome completo", stepAskName)
+			bt.expect(tt.text, "Obrigada, "+firstName(tt.want)+"!", stepAskDate)
+
+			patient, err := bt.patients.GetByPhone(context.Background(), testSender)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if patient.Name != tt.want {
+				t.Errorf("patient name: got %q, want %q", patient.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookingKnownPatientWithTime(t *testing.T) {
+	bt := newBookingTest(t, time.Hour)
+	bt.register("João Souza")
+
+	// Free exact time skips the slot list
+	bt.expect("quero marcar dia "+bt.monday.Format("2")+" às 14h", "Confirma a consulta em seg "+bt.day+", 14:00–14:50?", stepConfirm)
+	bt.expect("pode ser", "Pronto!", "")
+
+	// Taken time lists the others
+	bt.expect("quero agendar "+bt.day+" às 14h", "O horário das 14:00 não está disponível", stepChooseSlot)
+	bt.expect("15:00", "15:00–15:50", stepConfirm)
+	bt.expect("sim", "Pronto!", "")
+
+	if n := len(bt.booked()); n != 2 {
+		t.Errorf("booked: got %d appointments, want 2", n)
+	}
+}
+
+func TestBookingRepromptsOnBadInput(t *testing.T) {
+	bt := newBookingTest(t, time.Hour)
+	saturday := bt.monday.AddDate(0, 0, 5).Format("02/01")
+
+	bt.expect("agendar", "nome completo", stepAskName)
+	bt.expect("123", "Não entendi. Por favor, informe seu nome completo", stepAskName)
+	bt.expect("Ana", "Obrigada, Ana!", stepAskDate)
+
+	bt.expect("qualquer dia", "Não consegui entender a data", stepAskDate)
+	bt.expect("31/02", "Não consegui entender a data", stepAskDate)
+	bt.expect("01/01/2020", "Essa data já passou", stepAskDate)
+	bt.expect(saturday, "Não há horários disponíveis em sáb "+saturday, stepAskDate)
+	bt.expect(bt.day, "Horários disponíveis", stepChooseSlot)
+
+	bt.expect("42", "Não encontrei essa opção", stepChooseSlot)
+	bt.expect("o mais cedo", "Não encontrei essa opção", stepChooseSlot)
+	bt.expect("às 12h", "Não encontrei essa opção", stepChooseSlot)
+	// Not an option number (8 are listed) but a listed hour
+	bt.expect("9", "09:00–09:50", stepConfirm)
+
+	bt.expect("talvez", "Por favor, responda \"sim\"", stepConfirm)
+	bt.expect("não", "Para qual outro dia", stepAskDate)
+	bt.expect("terça "+bt.monday.AddDate(0, 0, 1).Format("02/01")+" às 10h", "Confirma a consulta em ter", stepConfirm)
+	bt.expect("sim", "Pronto!", "")
+
+	booked := bt.booked()
+	if len(booked) != 1 || !booked[0].DateTime.Equal(bt.at(24+10)) {
+		t.Errorf("booked: got %d appointments, want one on Tuesday 10:00", len(booked))
+	}
+}
+
+func TestBookingCancelFromEachStep(t *testing.T) {
+	tests := []struct {
+		step  string
+		known bool
+		texts []string
+	}{
+		{stepAskName, false, []string{"quero agendar"}},
+		{stepAskDate, true, []string{"quero agendar"}},
+		{stepAskDate, false, []string{"quero agendar", "Carla Dias"}},
+		{stepChooseSlot, true, []string{"quero agendar", "DAY"}},
+		{stepConfirm, true, []string{"quero agendar", "DAY", "1"}},
+		{stepConfirm, true, []string{"quero agendar DAY às 10h"}},
+	}
+
+	for _, escape := range []string{"cancelar", "Cancela!", "sair"} {
+		for _, tt := range tests {
+			t.Run(tt.step+"/"+escape, func(t *testing.T) {
+				bt := newBookingTest(t, time.Hour)
+				if tt.known {
+					bt.register("Carla Dias")
+				}
+				for _, text := range tt.texts {
+					bt.send(strings.ReplaceAll(text, "DAY", bt.day))
+				}
+				if got := bt.step(); got != tt.step {
+					t.Fatalf("step before %q: got %q, want %q", escape, got, tt.step)
+				}
+
+				bt.expect(escape, "Agendamento cancelado", "")
+				if n := len(bt.booked()); n != 0 {
+					t.Errorf("booked: got %d appointments, want none", n)
+				}
+
+				// Next message is a fresh conversation
+				if got := bt.send("sim"); got != "" {
+					t.Errorf("reply to %q after cancelling: got %q, want none", "sim", got)
+				}
+			})
+		}
+	}
+}
+
+func TestBookingSessionExpiresMidFlow(t *testing.T) {
+	const ttl = 200 * time.Millisecond
+	bt := newBookingTest(t, ttl)
+	bt.register("Pedro Lima")
+
+	bt.expect("quero agendar", "Olá, Pedro!", stepAskDate)
+	bt.expect(bt.day, "Horários disponíveis", stepChooseSlot)
+	time.Sleep(ttl + 50*time.Millisecond)
+
+	// Answers to the expired dialog are not taken as a booking
+	if got := bt.send("2"); got != "" {
+		t.Errorf("reply to slot choice after expiry: got %q, want none", got)
+	}
+	if got := bt.send("sim"); got != "" {
+		t.Errorf("reply to confirmation after expiry: got %q, want none", got)
+	}
+	if got := bt.step(); got != "" {
+		t.Errorf("step after expiry: got %q, want no session", got)
+	}
+	if n := len(bt.booked()); n != 0 {
+		t.Errorf("booked: got %d appointments, want none", n)
+	}
+
+	// Starting over works from the date step
+	bt.expect("quero agendar", "Olá, Pedro! Para qual dia", stepAskDate)
+	bt.expect(bt.day+" às 11h", "11:00–11:50", stepConfirm)
+	bt.expect("sim", "Pronto!", "")
+	if n := len(bt.booked()); n != 1 {
+		t.Errorf("booked: got %d appointments, want 1", n)
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+)
+
+// weekdayShort holds Portuguese weekday abbreviations.
+var weekdayShort = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+var (
+	yesPattern    = regexp.MustCompile(`^(sim|s|ok|confirmo|confirmar|confirma|certo|isso|pode\s+ser|claro|1)\b`)
+	noPattern     = regexp.MustCompile(`^(nao|n|2)\b`)
+	escapePattern = regexp.MustCompile(`^(cancelar|cancela|sair|parar|desistir)[.!]*$`)
+)
+
+// formatDay formats day as "ter 20/10".
+func formatDay(t time.Time) string {
+	return fmt.Sprintf("%s %s", weekdayShort[t.Weekday()], t.Format("02/01"))
+}
+
+// formatClock formats time as "14:00".
+func formatClock(t time.Time) string {
+	return t.Format("15:04")
+}
+
+// isYes reports whether text is an affirmative answer.
+func isYes(text string) bool {
+	return yesPattern.MatchString(nlp.Normalize(text))
+}
+
+// isNo reports whether text is a negative answer.
+func isNo(text string) bool {
+	return noPattern.MatchString(nlp.Normalize(text))
+}
+
+// isEscape reports whether text asks to abandon the current dialog.
+func isEscape(text string) bool {
+	return escapePattern.MatchString(nlp.Normalize(text))
+}
+
+// formatRange formats time range as "14:00–14:50".
+func formatRange(start, end time.Time) string {
+	return formatClock(start) + "–" + formatClock(end)
+}
+
+// reply builds single-reply result.
+func reply(format string, args ...any) []domain.Reply {
+	return []domain.Reply{{Text: fmt.Sprintf(format, args...)}}
+}
+
+// numberedList renders options as "1) a\n2) b".
+func numberedList(options []string) string {
+	var b strings.Builder
+	for i, opt := range options {
+		fmt.Fprintf(&b, "%d) %s\n", i+1, opt)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -1,14 +1,43 @@
 package handler
 
 import (
-	"go.mau.fi/whatsmeow/types/events"
+	"context"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
 )
 
-// MessageHandler defines interface for WhatsApp message handling.
-// Future phases will implement routing logic (NLP, appointment scheduling).
+// MessageHandler defines interface for inbound message handling.
 type MessageHandler interface {
-	// Handle processes incoming WhatsApp message.
-	Handle(msg *events.Message) error
+	// Handle processes incoming message, returns zero or more replies to send.
+	Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error)
+}
+
+// Chain runs handlers in order until one produces replies.
+type Chain []MessageHandler
+
+// NewChain creates handler chain from handlers (evaluated in order).
+func NewChain(handlers ...MessageHandler) Chain {
+	return Chain(handlers)
+}
+
+// Handle passes message down the chain.
+// Stops at first handler returning replies or error.
+// Writes made while handling are attributed to the patient and to msg.
+func (c Chain) Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	ctx = repository.WithActor(ctx, domain.ActorPatient)
+	ctx = repository.WithCorrelationID(ctx, msg.ID)
+
+	for _, h := range c {
+		replies, err := h.Handle(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		if len(replies) > 0 {
+			return replies, nil
+		}
+	}
+	return nil, nil
 }
 
 // EchoHandler implements simple echo functionality for testing.
@@ -19,10 +48,21 @@ func NewEchoHandler() *EchoHandler {
 	return &EchoHandler{}
 }
 
-// Handle echoes message back (implementation in whatsapp package).
-// This is a placeholder interface for future multi-handler routing.
-func (h *EchoHandler) Handle(msg *events.Message) error {
-	// Echo logic is currently in whatsapp/message.go
-	// In future phases, this will route to NLP → service → repo
-	return nil
+// Handle replies with fixed test message.
+func (h *EchoHandler) Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	return []domain.Reply{{Text: "Clara: Testing"}}, nil
+}
+
+// HelpHandler replies with usage hint. Meant as last handler in chain.
+type HelpHandler struct{}
+
+// NewHelpHandler creates help handler instance.
+func NewHelpHandler() *HelpHandler {
+	return &HelpHandler{}
+}
+
+// Handle replies with what the assistant can do.
+func (h *HelpHandler) Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	return []domain.Reply{{Text: "Olá! Sou a Clara, assistente de agendamentos da clínica. " +
+		"Para marcar uma consulta, é só me dizer \"quero marcar uma consulta\"."}}, nil
 }
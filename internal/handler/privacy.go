@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Erasure flow steps:
+//
+//	("apagar meus dados") → confirm_erasure
+//	confirm_erasure       → done ("apagar": patient erased; anything else keeps data)
+const (
+	flowErasure = "erasure"
+
+	stepConfirmErasure = "confirm_erasure"
+)
+
+var (
+	exportPattern       = regexp.MustCompile(`^(exportar\s+|baixar\s+|ver\s+)?meus\s+dados[.!?]*$`)
+	erasurePattern      = regexp.MustCompile(`^(apagar|excluir|deletar|remover)\s+meus\s+dados[.!?]*$`)
+	confirmErasePattern = regexp.MustCompile(`^apagar[.!]*$`)
+)
+
+// PrivacyHandler answers data subject requests sent over WhatsApp:
+// "meus dados" replies with a JSON export of the sender's data, and
+// "apagar meus dados" erases it after confirmation. Passes other messages
+// through unless an erasure awaits confirmation.
+type PrivacyHandler struct {
+	patients *service.PatientService
+	privacy  *service.PrivacyService
+	sessions repository.SessionRepository
+	now      func() time.Time
+}
+
+// NewPrivacyHandler creates data subject request handler.
+func NewPrivacyHandler(
+	patients *service.PatientService,
+	privacy *service.PrivacyService,
+	sessions repository.SessionRepository,
+) *PrivacyHandler {
+	return &PrivacyHandler{
+		patients: patients,
+		privacy:  privacy,
+		sessions: sessions,
+		now:      time.Now,
+	}
+}
+
+// Handle serves export and erasure commands.
+func (h *PrivacyHandler) Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	session, err := h.sessions.Get(ctx, msg.Sender)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load session: %w", err)
+		}
+		session = domain.NewSession(msg.Sender)
+	}
+	if session.Flow == flowErasure {
+		return h.handleConfirm(ctx, session, msg)
+	}
+
+	text := nlp.Normalize(msg.Text)
+	switch {
+	case exportPattern.MatchString(text):
+		return h.export(ctx, msg)
+	case erasurePattern.MatchString(text):
+		return h.startErasure(ctx, session, msg)
+	}
+	return nil, nil
+}
+
+// export replies with sender's data as a JSON document.
+func (h *PrivacyHandler) export(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	patient, err := h.patients.GetByPhone(ctx, msg.Sender)
+	if errors.Is(err, repository.ErrNotFound) {
+		return reply("Não encontrei dados associados a este número."), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	export, err := h.privacy.Export(ctx, patient.ID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export: %w", err)
+	}
+
+	return []domain.Reply{{
+		Text: "Aqui estão todos os dados que guardamos sobre você.",
+		Document: &domain.Document{
+			FileName: "meus-dados-" + h.now().Format("2006-01-02") + ".json",
+			MimeType: "application/json",
+			Data:     data,
+		},
+	}}, nil
+}
+
+// startErasure asks sender to confirm erasure of their data.
+func (h *PrivacyHandler) startErasure(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	patient, err := h.patients.GetByPhone(ctx, msg.Sender)
+	if errors.Is(err, repository.ErrNotFound) {
+		return reply("Não encontrei dados associados a este número."), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	s.Reset()
+	s.Flow = flowErasure
+	s.Step = stepConfirmErasure
+	s.Slots[slotPatientID] = patient.ID.Hex()
+	if err := h.sessions.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return reply("Isso apaga seu nome e telefone dos nossos registros e cancela suas consultas futuras. " +
+		"Não é possível desfazer.\n\nPara confirmar, responda \"apagar\". Qualquer outra resposta mantém seus dados."), nil
+}
+
+// handleConfirm erases patient if confirmed, ending the flow either way.
+func (h *PrivacyHandler) handleConfirm(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	if !confirmErasePattern.MatchString(nlp.Normalize(msg.Text)) {
+		return h.finish(ctx, s, reply("Ok, seus dados foram mantidos."))
+	}
+
+	id, err := primitive.ObjectIDFromHex(s.Slots[slotPatientID])
+	if err != nil {
+		return nil, fmt.Errorf("invalid patient id in session: %w", err)
+	}
+	if err := h.privacy.Erase(ctx, id); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	return h.finish(ctx, s, reply("Pronto, seus dados foram apagados. Obrigado por ter sido nosso paciente."))
+}
+
+// finish ends conversation state and returns replies.
+func (h *PrivacyHandler) finish(ctx context.Context, s *domain.Session, replies []domain.Reply) ([]domain.Reply, error) {
+	if err := h.sessions.Delete(ctx, s.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to delete session: %w", err)
+	}
+	return replies, nil
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// ReminderReplyHandler confirms or cancels appointments from replies to reminders.
+// A reply is matched to the quoted reminder, or else to the patient's most
+// recent reminder for an upcoming appointment sent within service.ReplyWindow.
+// Passes through when the message is neither a confirmation nor a
+// cancellation, or no reminder matches.
+//
+// Without a quote, cancelling takes an explicit phrase: a terse "não" or "2"
+// may answer something else, so it only gets asked to confirm.
+type ReminderReplyHandler struct {
+	reminders  *service.ReminderService
+	scheduling *service.SchedulingService
+	classifier nlp.IntentClassifier
+	loc        *time.Location
+	now        func() time.Time
+}
+
+// minUnquotedCancelConfidence is needed to cancel from a reply that does not
+// quote the reminder. Rule matches of bare "não", "n" or "2" score below it.
+const minUnquotedCancelConfidence = 0.8
+
+// NewReminderReplyHandler creates reminder reply handler.
+func NewReminderReplyHandler(
+	reminders *service.ReminderService,
+	scheduling *service.SchedulingService,
+	classifier nlp.IntentClassifier,
+	loc *time.Location,
+) *ReminderReplyHandler {
+	return &ReminderReplyHandler{
+		reminders:  reminders,
+		scheduling: scheduling,
+		classifier: classifier,
+		loc:        loc,
+		now:        time.Now,
+	}
+}
+
+// Handle applies confirm/cancel reply to the reminded appointment.
+func (h *ReminderReplyHandler) Handle(ctx context.Context, msg *domain.Message) ([]domain.Reply, error) {
+	var quotedID string
+	if msg.Quoted != nil {
+		quotedID = msg.Quoted.ID
+	}
+
+	// Lookup first: cheaper than classifying every message
+	reminder, err := h.reminders.ForReply(ctx, msg.Sender, quotedID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.classifier.Classify(ctx, msg.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify message: %w", err)
+	}
+	if result.Intent != nlp.IntentConfirm && result.Intent != nlp.IntentCancel {
+		return nil, nil
+	}
+
+	apt, err := h.scheduling.Get(ctx, reminder.Appointment)
+	if errors.Is(err, repository.ErrNotFound) {
+		return reply("Não encontrei mais essa consulta. Se quiser agendar, é só dizer \"agendar\"."), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+
+	start := apt.DateTime.In(h.loc)
+	when := fmt.Sprintf("%s, %s", formatDay(start), formatRange(start, apt.End().In(h.loc)))
+	if !start.After(h.now()) {
+		return reply("A consulta de %s já passou.", when), nil
+	}
+
+	switch {
+	case apt.Status == domain.StatusCancelled:
+		return reply("Sua consulta de %s já estava cancelada. Se quiser remarcar, é só dizer \"agendar\".", when), nil
+	case apt.Status == domain.StatusConfirmed && result.Intent == nlp.IntentConfirm:
+		return reply("Sua consulta de %s já está confirmada. Até lá!", when), nil
+	}
+
+	quoted := quotedID != "" && reminder.MessageID == quotedID
+	if result.Intent == nlp.IntentCancel && !quoted && result.Confidence < minUnquotedCancelConfidence {
+		return reply("Se quiser cancelar a consulta de %s, responda \"cancelar\".", when), nil
+	}
+
+	if result.Intent == nlp.IntentConfirm {
+		_, err = h.scheduling.Confirm(ctx, apt.ID, domain.ActorPatient, "reminder reply")
+	} else {
+		_, err = h.scheduling.Cancel(ctx, apt.ID, domain.ActorPatient, "reminder reply")
+	}
+	switch {
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, repository.ErrConflict):
+		return reply("Não foi possível alterar a consulta de %s. Por favor, fale com a clínica.", when), nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to update appointment: %w", err)
+	}
+
+	log.Info().
+		Str("appointment_id", apt.ID.Hex()).
+		Str("reminder_id", reminder.ID.Hex()).
+		Str("intent", string(result.Intent)).
+		Msg("appointment updated from reminder reply")
+
+	if result.Intent == nlp.IntentConfirm {
+		return reply("Obrigada! Sua consulta de %s está confirmada.", when), nil
+	}
+	return reply("Tudo bem, sua consulta de %s foi cancelada. Se quiser remarcar, é só dizer \"agendar\".", when), nil
+}
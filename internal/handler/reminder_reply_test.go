@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/memory"
+	"github.com/matheusmassa1/clara/internal/service"
+)
+
+// replyTest holds a patient with an appointment tomorrow and its reminder.
+type replyTest struct {
+	t            *testing.T
+	handler      *ReminderReplyHandler
+	appointments repository.AppointmentRepository
+	apt          *domain.Appointment
+}
+
+// newReplyTest stores the reminder as sent messageID, sentAgo before now.
+func newReplyTest(t *testing.T, messageID string, sentAgo time.Duration) *replyTest {
+	t.Helper()
+	ctx := context.Background()
+	appointments := memory.NewAppointmentRepository()
+	patients := service.NewPatientService(memory.NewPatientRepository(appointments))
+	reminders := memory.NewReminderRepository()
+
+	patient, err := patients.Register(ctx, "Maria da Silva", testSender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, m, d := time.Now().In(testLocation).AddDate(0, 0, 1).Date()
+	start := time.Date(y, m, d, 14, 0, 0, 0, testLocation)
+	apt := &domain.Appointment{DateTime: start, EndTime: start.Add(50 * time.Minute), Patient: patient.ID}
+	if err := apt.Transition(domain.StatusPending, domain.ActorPatient, "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := appointments.Create(ctx, apt); err != nil {
+		t.Fatal(err)
+	}
+
+	rem := &domain.Reminder{Appointment: apt.ID, Patient: patient.ID, AppointmentAt: start, Offset: 24 * time.Hour}
+	if err := reminders.Create(ctx, rem); err != nil {
+		t.Fatal(err)
+	}
+	if err := reminders.MarkSent(ctx, rem.ID, messageID, time.Now().Add(-sentAgo)); err != nil {
+		t.Fatal(err)
+	}
+
+	return &replyTest{
+		t: t,
+		handler: NewReminderReplyHandler(service.NewReminderService(reminders, patients),
+			newTestScheduling(t, appointments), nlp.NewRuleClassifier(), testLocation),
+		appointments: appointments,
+		apt:          apt,
+	}
+}
+
+// send delivers text, quoting message quoted unless empty, and returns the replies joined.
+func (rt *replyTest) send(text, quoted string) string {
+	rt.t.Helper()
+	msg := &domain.Message{ID: "in", Sender: testSender, Text: text}
+	if quoted != "" {
+		msg.Quoted = &domain.Quoted{ID: quoted}
+	}
+	replies, err := rt.handler.Handle(context.Background(), msg)
+	if err != nil {
+		rt.t.Fatalf("Handle(%q): %v", text, err)
+	}
+	texts := make([]string, len(replies))
+	for i, r := range replies {
+		texts[i] = r.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// status returns stored status of the reminded appointment.
+func (rt *replyTest) status() string {
+	rt.t.Helper()
+	apt, err := rt.appointments.GetByID(context.Background(), rt.apt.ID)
+	if err != nil {
+		rt.t.Fatal(err)
+	}
+	return apt.Status
+}
+
+func TestReminderReplyQuoted(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		status string
+	}{
+		{"sim", "Obrigada! Sua consulta de", domain.StatusConfirmed},
+		{"Confirmo, estarei lá", "está confirmada", domain.StatusConfirmed},
+		// The quote makes terse answers unambiguous
+		{"não", "foi cancelada", domain.StatusCancelled},
+		{"2", "foi cancelada", domain.StatusCancelled},
+		{"qual o endereço?", "", domain.StatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			// Quoted replies are matched however long ago the reminder went out
+			rt := newReplyTest(t, "MSG-1", 20*time.Hour)
+			got := rt.send(tt.text, "MSG-1")
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("reply: got %q, want %q", got, tt.want)
+			}
+			if status := rt.status(); status != tt.status {
+				t.Errorf("status: got %s, want %s", status, tt.status)
+			}
+		})
+	}
+
+	rt := newReplyTest(t, "MSG-1", time.Hour)
+	rt.send("sim", "MSG-1")
+	if got := rt.send("ok", "MSG-1"); !strings.Contains(got, "já está confirmada") {
+		t.Errorf("confirm twice: got %q", got)
+	}
+	rt.send("cancelar", "MSG-1")
+	if got := rt.send("cancelar", "MSG-1"); !strings.Contains(got, "já estava cancelada") {
+		t.Errorf("cancel twice: got %q", got)
+	}
+}
+
+func TestReminderReplyFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		quoted  string
+		sentAgo time.Duration
+		want    string
+		status  string
+	}{
+		{"confirm", "confirmo", "", time.Hour, "está confirmada", domain.StatusConfirmed},
+		{"explicit cancel", "não vou poder ir", "", time.Hour, "foi cancelada", domain.StatusCancelled},
+		{"quoting another message", "cancelar", "OTHER", time.Hour, "foi cancelada", domain.StatusCancelled},
+		{"unrelated message", "bom dia", "", time.Hour, "", domain.StatusPending},
+		{"outside reply window", "confirmo", "", service.ReplyWindow + time.Hour, "", domain.StatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newReplyTest(t, "MSG-1", tt.sentAgo)
+			got := rt.send(tt.text, tt.quoted)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("reply: got %q, want %q", got, tt.want)
+			}
+			if status := rt.status(); status != tt.status {
+				t.Errorf("status: got %s, want %s", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestReminderReplyStrayAnswerDoesNotCancel(t *testing.T) {
+	for _, text := range []string{"2", "n", "não"} {
+		t.Run(text, func(t *testing.T) {
+			// Recent reminder: asked to confirm with an explicit phrase
+			rt := newReplyTest(t, "MSG-1", time.Hour)
+			if got := rt.send(text, ""); !strings.Contains(got, "responda \"cancelar\"") {
+				t.Errorf("reply: got %q, want request for explicit cancel", got)
+			}
+			if status := rt.status(); status != domain.StatusPending {
+				t.Errorf("status: got %s, want %s", status, domain.StatusPending)
+			}
+			if got := rt.send("cancelar", ""); !strings.Contains(got, "foi cancelada") {
+				t.Errorf("explicit cancel: got %q", got)
+			}
+
+			// Old reminder: not taken as its answer at all
+			rt = newReplyTest(t, "MSG-1", 2*service.ReplyWindow)
+			if got := rt.send(text, ""); got != "" {
+				t.Errorf("reply after window: got %q, want none", got)
+			}
+			if status := rt.status(); status != domain.StatusPending {
+				t.Errorf("status after window: got %s, want %s", status, domain.StatusPending)
+			}
+		})
+	}
+}
+
+func TestReminderReplyUnknownSender(t *testing.T) {
+	rt := newReplyTest(t, "MSG-1", time.Hour)
+	replies, err := rt.handler.Handle(context.Background(), &domain.Message{ID: "in", Sender: "+5511900000000", Text: "cancelar"})
+	if err != nil || len(replies) != 0 {
+		t.Errorf("Handle from unknown sender: got %v, %v, want no replies", replies, err)
+	}
+	if status := rt.status(); status != domain.StatusPending {
+		t.Errorf("status: got %s, want %s", status, domain.StatusPending)
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/nlp"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// startReschedule enters reschedule flow: pick appointment, then date.
+func (h *BookingHandler) startReschedule(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	s.Reset()
+
+	patient, err := h.patients.GetByPhone(ctx, msg.Sender)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get patient: %w", err)
+		}
+		return reply("Não encontrei consultas para este número. Se quiser agendar, é só dizer \"agendar\"."), nil
+	}
+
+	upcoming, err := h.scheduling.Upcoming(ctx, patient.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(upcoming) == 0 {
+		return reply("Você não tem consultas futuras para remarcar. Se quiser agendar, é só dizer \"agendar\"."), nil
+	}
+
+	s.Flow = flowReschedule
+	s.Slots[slotPatientID] = patient.ID.Hex()
+
+	if len(upcoming) == 1 {
+		return h.chooseAppointment(ctx, s, msg, upcoming[0])
+	}
+
+	ids := make([]string, len(upcoming))
+	labels := make([]string, len(upcoming))
+	for i, apt := range upcoming {
+		ids[i] = apt.ID.Hex()
+		labels[i] = h.describe(apt)
+	}
+	s.Slots[slotCandidates] = strings.Join(ids, ",")
+	s.Slots[slotRequest] = msg.Text // May name the new date; used once appointment is picked
+	s.Step = stepChooseAppointment
+	return reply("Qual consulta você gostaria de remarcar?\n%s\nResponda com o número.", numberedList(labels)), nil
+}
+
+// handleAppointmentChoice accepts option number from candidate list.
+func (h *BookingHandler) handleAppointmentChoice(ctx context.Context, s *domain.Session, msg *domain.Message) ([]domain.Reply, error) {
+	ids := strings.Split(s.Slots[slotCandidates], ",")
+
+	n, err := strconv.Atoi(strings.Trim(strings.TrimSpace(msg.Text), ")."))
+	if err != nil || n < 1 || n > len(ids) {
+		return reply("Não encontrei essa opção. Responda com o número da consulta (ou \"cancelar\" para sair)."), nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(ids[n-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid appointment id in session: %w", err)
+	}
+	apt, err := h.scheduling.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+
+	request := &domain.Message{Sender: msg.Sender, Text: s.Slots[slotRequest]}
+	delete(s.Slots, slotCandidates)
+	delete(s.Slots, slotRequest)
+	return h.chooseAppointment(ctx, s, request, apt)
+}
+
+// chooseAppointment stores appointment to move, then asks for (or uses) new date.
+func (h *BookingHandler) chooseAppointment(ctx context.Context, s *domain.Session, msg *domain.Message, apt *domain.Appointment) ([]domain.Reply, error) {
+	s.Slots[slotAppointmentID] = apt.ID.Hex()
+	s.Slots[slotMinutes] = strconv.Itoa(int(apt.Duration() / time.Minute))
+	s.Step = stepAskDate
+
+	// Message may already carry the new date ("posso remarcar para quinta?")
+	if _, err := nlp.ParseDateTime(msg.Text, h.now(), h.loc); err == nil {
+		return h.handleDate(ctx, s, msg)
+	}
+
+	return reply("Sua consulta é %s. Para qual dia você gostaria de remarcar?", h.describe(apt)), nil
+}
+
+// confirmReschedule moves appointment to chosen start.
+func (h *BookingHandler) confirmReschedule(ctx context.Context, s *domain.Session, start time.Time) ([]domain.Reply, error) {
+	id, err := primitive.ObjectIDFromHex(s.Slots[slotAppointmentID])
+	if err != nil {
+		return nil, fmt.Errorf("invalid appointment id in session: %w", err)
+	}
+
+	apt, err := h.scheduling.Reschedule(ctx, id, start, domain.ActorPatient, "rescheduled via whatsapp")
+	switch {
+	case errors.Is(err, service.ErrSlotUnavailable), errors.Is(err, service.ErrInPast):
+		delete(s.Slots, slotChosen)
+		delete(s.Slots, slotOffered)
+		s.Step = stepAskDate
+		return reply("Que pena, esse horário não está mais disponível. Para qual dia você gostaria de remarcar?"), nil
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, repository.ErrNotFound):
+		s.Reset()
+		return reply("Essa consulta não pode mais ser remarcada. Por favor, fale com a clínica."), nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to reschedule appointment: %w", err)
+	}
+
+	s.Reset()
+	return reply("Pronto! Sua consulta foi remarcada para %s.", h.describe(apt)), nil
+}
+
+// describe formats appointment as "ter 20/10, 14:00–14:50".
+func (h *BookingHandler) describe(apt *domain.Appointment) string {
+	start := apt.DateTime.In(h.loc)
+	return fmt.Sprintf("%s, %s", formatDay(start), formatRange(start, apt.End().In(h.loc)))
+}
@@ -0,0 +1,93 @@
+package nlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/config"
+)
+
+// HFClassifier implements IntentClassifier using a Hugging Face
+// text-classification model.
+type HFClassifier struct {
+	client *hfClient
+	model  string
+	labels map[string]Intent
+}
+
+// labelScore is a single text-classification prediction.
+type labelScore struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// NewHFClassifier creates classifier for cfg.HFIntentModel.
+// Returns error if HF_INTENT_LABELS is malformed.
+func NewHFClassifier(cfg *config.Config) (*HFClassifier, error) {
+	labels, err := ParseLabelMap(cfg.HFIntentLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse intent labels: %w", err)
+	}
+
+	timeout := time.Duration(cfg.HFTimeout) * time.Second
+	return &HFClassifier{
+		client: newHFClient(cfg.HFAPIURL, cfg.HFAPIKey, timeout, cfg.HFMaxRetries),
+		model:  cfg.HFIntentModel,
+		labels: labels,
+	}, nil
+}
+
+// Classify sends text to the model and maps best label to an intent.
+// Unmapped labels yield IntentUnknown with the model's score.
+func (c *HFClassifier) Classify(ctx context.Context, text string) (*Classification, error) {
+	var raw json.RawMessage
+	if err := c.client.infer(ctx, c.model, map[string]string{"inputs": text}, &raw); err != nil {
+		return nil, fmt.Errorf("failed to classify intent: %w", err)
+	}
+
+	predictions, err := decodeLabelScores(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *labelScore
+	for i := range predictions {
+		if best == nil || predictions[i].Score > best.Score {
+			best = &predictions[i]
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: no predictions", ErrUnexpectedResponse)
+	}
+
+	intent, ok := c.labels[strings.ToLower(best.Label)]
+	if !ok {
+		intent = IntentUnknown
+	}
+
+	return &Classification{
+		Intent:     intent,
+		Confidence: best.Score,
+		Label:      best.Label,
+	}, nil
+}
+
+// decodeLabelScores accepts both batched ([[...]]) and flat ([...]) responses.
+func decodeLabelScores(raw json.RawMessage) ([]labelScore, error) {
+	var batched [][]labelScore
+	if err := json.Unmarshal(raw, &batched); err == nil {
+		if len(batched) == 0 {
+			return nil, nil
+		}
+		return batched[0], nil
+	}
+
+	var flat []labelScore
+	if err := json.Unmarshal(raw, &flat); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	return flat, nil
+}
@@ -0,0 +1,546 @@
+package nlp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a coarse part of the day ("de manhã", "à tarde", "à noite").
+type Period string
+
+// Period constants
+const (
+	PeriodNone      Period = ""
+	PeriodMorning   Period = "morning"
+	PeriodAfternoon Period = "afternoon"
+	PeriodEvening   Period = "evening"
+)
+
+// Ambiguity flags underspecified parts of a date/time expression.
+type Ambiguity string
+
+// Ambiguity constants
+const (
+	// AmbiguityNoDate: only a time was given; date assumed (today, or tomorrow if time passed).
+	AmbiguityNoDate Ambiguity = "no_date"
+	// AmbiguityNoTime: only a date was given; time set to midnight.
+	AmbiguityNoTime Ambiguity = "no_time"
+	// AmbiguityPeriodOnly: only a part of day was given; time set to its typical start.
+	AmbiguityPeriodOnly Ambiguity = "period_only"
+	// AmbiguityMeridiem: hour could be AM or PM ("às 3"); clinic hours assumed.
+	AmbiguityMeridiem Ambiguity = "meridiem"
+	// AmbiguityVagueDate: date given as a span ("semana que vem"); first day assumed.
+	AmbiguityVagueDate Ambiguity = "vague_date"
+	// AmbiguityNextWeek: "próxima sexta"/"sexta que vem" named a day still in the
+	// current week (Monday to Sunday); the one in the following week assumed.
+	AmbiguityNextWeek Ambiguity = "next_week"
+	// AmbiguityConflict: weekday does not match explicit date ("sexta dia 3").
+	AmbiguityConflict Ambiguity = "conflict"
+	// AmbiguityPast: resolved time is before the reference time.
+	AmbiguityPast Ambiguity = "past"
+)
+
+// DateTime is a parsed date/time expression.
+type DateTime struct {
+	Time        time.Time // Resolved instant in the clinic timezone
+	HasDate     bool
+	HasTime     bool
+	Period      Period
+	Ambiguities []Ambiguity
+}
+
+// Ambiguous reports whether any part of the expression was assumed.
+func (d *DateTime) Ambiguous() bool {
+	return len(d.Ambiguities) > 0
+}
+
+// Has reports whether ambiguity a was flagged.
+func (d *DateTime) Has(a Ambiguity) bool {
+	for _, x := range d.Ambiguities {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
+
+// periodStart is the hour assumed when only a period is given.
+var periodStart = map[Period]int{
+	PeriodMorning:   9,
+	PeriodAfternoon: 14,
+	PeriodEvening:   19,
+}
+
+// accentReplacer strips Portuguese diacritics after lowercasing.
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// numberWords maps spelled-out numbers used for hours and counts.
+var numberWords = map[string]int{
+	"um": 1, "uma": 1, "dois": 2, "duas": 2, "tres": 3, "quatro": 4,
+	"cinco": 5, "seis": 6, "sete": 7, "oito": 8, "nove": 9, "dez": 10,
+	"onze": 11, "doze": 12, "quinze": 15, "vinte": 20, "trinta": 30,
+}
+
+var monthNames = map[string]time.Month{
+	"janeiro": time.January, "fevereiro": time.February, "marco": time.March,
+	"abril": time.April, "maio": time.May, "junho": time.June,
+	"julho": time.July, "agosto": time.August, "setembro": time.September,
+	"outubro": time.October, "novembro": time.November, "dezembro": time.December,
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"domingo": time.Sunday, "segunda": time.Monday, "terca": time.Tuesday,
+	"quarta": time.Wednesday, "quinta": time.Thursday, "sexta": time.Friday,
+	"sabado": time.Saturday,
+}
+
+// Patterns run against lowercased, accent-free text.
+const (
+	numWord   = `\d{1,2}|um|uma|dois|duas|tres|quatro|cinco|seis|sete|oito|nove|dez|onze|doze|quinze|vinte|trinta`
+	monthWord = `janeiro|fevereiro|marco|abril|maio|junho|julho|agosto|setembro|outubro|novembro|dezembro`
+	dayWord   = `segunda|terca|quarta|quinta|sexta|sabado|domingo`
+)
+
+var (
+	reRelativeTime = regexp.MustCompile(`\b(?:daqui\s+a|daqui|em|dentro\s+de)\s+(` + numWord + `|meia)\s+(horas?|minutos?|hora)\b`)
+	reRelativeDate = regexp.MustCompile(`\b(?:daqui\s+a|daqui|em|dentro\s+de)\s+(` + numWord + `)\s+(dias?|semanas?|mes|meses)\b`)
+	reRelativeDay  = regexp.MustCompile(`\b(depois\s+de\s+amanha|amanha|hoje)\b`)
+	reMonthDate    = regexp.MustCompile(`\b(?:dia\s+)?(\d{1,2})\s+de\s+(` + monthWord + `)(?:\s+de\s+(\d{4}))?\b`)
+	reNumericDate  = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})(?:/(\d{2,4}))?\b`)
+	reDayOfMonth   = regexp.MustCompile(`\bdia\s+(\d{1,2})\b`)
+	reWeekday      = regexp.MustCompile(`\b(?:(proxim[ao]|nest[ae]|ness[ae]|est[ae]|ess[ae])\s+)?(` + dayWord + `)(?:[- ]feira)?(\s+que\s+vem)?\b`)
+	reVagueWeek    = regexp.MustCompile(`\b(?:proxima\s+semana|semana\s+que\s+vem)\b`)
+	reVagueMonth   = regexp.MustCompile(`\b(?:proximo\s+mes|mes\s+que\s+vem)\b`)
+
+	reNoon     = regexp.MustCompile(`\b(meio[- ]dia|meia[- ]noite)(?:\s+e\s+(meia|quinze|\d{1,2}))?\b`)
+	reClock    = regexp.MustCompile(`\b(\d{1,2})\s*(?::|h)\s*(\d{2})\b`)
+	reHourUnit = regexp.MustCompile(`\b(\d{1,2})\s*(?:h|hs|hr|hrs|horas?)(?:\s+e\s+(meia|quinze))?\b`)
+	reHourPrep = regexp.MustCompile(`\b(?:as|das|pelas|ate\s+as|a\s+partir\s+das)\s+(` + numWord + `)(?:\s+e\s+(meia|quinze|\d{1,2}))?\b`)
+	rePeriod   = regexp.MustCompile(`\b(?:de|pela|a|na|no|da|nesta|esta)\s+(manha|tarde|noite)\b`)
+)
+
+// ParseDateTime resolves a Portuguese date/time expression against ref
+// in loc (clinic timezone). Works offline and is deterministic.
+// Returns ErrNoDateTime if text carries neither date nor time.
+func ParseDateTime(text string, ref time.Time, loc *time.Location) (*DateTime, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	p := &dtParser{
+		text: " " + accentReplacer.Replace(strings.ToLower(text)) + " ",
+		ref:  ref.In(loc),
+		loc:  loc,
+	}
+	return p.parse()
+}
+
+// dtParser holds parse state. Matched spans are blanked out of text so
+// later rules never reinterpret them (e.g. "2 dias" as a time).
+type dtParser struct {
+	text string
+	ref  time.Time
+	loc  *time.Location
+
+	// Date parts
+	hasDate bool
+	date    time.Time // Midnight of resolved day
+
+	// Time parts
+	hasTime bool
+	hour    int
+	minute  int
+	period  Period
+
+	// exact is set when expression resolves to an instant ("daqui a 2 horas")
+	exact *time.Time
+
+	ambiguities []Ambiguity
+}
+
+// parse runs date rules, then time rules, and assembles the result.
+func (p *dtParser) parse() (*DateTime, error) {
+	if err := p.parseRelativeTime(); err != nil {
+		return nil, err
+	}
+	if p.exact != nil {
+		return p.result(*p.exact), nil
+	}
+
+	for _, step := range []func() error{p.parseDate, p.parseTime} {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.hasDate && !p.hasTime && p.period == PeriodNone {
+		return nil, ErrNoDateTime
+	}
+
+	hour, minute := p.hour, p.minute
+	if !p.hasTime {
+		if p.period != PeriodNone {
+			hour = periodStart[p.period]
+			p.flag(AmbiguityPeriodOnly)
+		} else {
+			p.flag(AmbiguityNoTime)
+		}
+	}
+
+	day := p.date
+	if !p.hasDate {
+		p.flag(AmbiguityNoDate)
+		day = midnight(p.ref)
+		if at(day, hour, minute).Before(p.ref) {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+
+	t := at(day, hour, minute)
+	if p.hasDate && (p.hasTime || p.period != PeriodNone) && t.Before(p.ref) {
+		p.flag(AmbiguityPast)
+	}
+	if p.hasDate && !p.hasTime && p.period == PeriodNone && day.Before(midnight(p.ref)) {
+		p.flag(AmbiguityPast)
+	}
+
+	return p.result(t), nil
+}
+
+// result builds DateTime from parser state.
+func (p *dtParser) result(t time.Time) *DateTime {
+	return &DateTime{
+		Time:        t,
+		HasDate:     p.hasDate,
+		HasTime:     p.hasTime,
+		Period:      p.period,
+		Ambiguities: p.ambiguities,
+	}
+}
+
+// parseRelativeTime handles "daqui a 2 horas", "em 30 minutos".
+func (p *dtParser) parseRelativeTime() error {
+	m := p.match(reRelativeTime)
+	if m == nil {
+		return nil
+	}
+
+	var d time.Duration
+	if m[1] == "meia" {
+		d = 30 * time.Minute
+	} else {
+		n, err := parseNumber(m[1])
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(m[2], "hora") {
+			d = time.Duration(n) * time.Hour
+		} else {
+			d = time.Duration(n) * time.Minute
+		}
+	}
+
+	t := p.ref.Add(d).Truncate(time.Minute)
+	p.exact = &t
+	p.hasDate, p.hasTime = true, true
+	return nil
+}
+
+// parseDate applies date rules in order of specificity.
+func (p *dtParser) parseDate() error {
+	today := midnight(p.ref)
+
+	if m := p.match(reRelativeDate); m != nil {
+		n, err := parseNumber(m[1])
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(m[2], "dia"):
+			p.setDate(today.AddDate(0, 0, n))
+		case strings.HasPrefix(m[2], "semana"):
+			p.setDate(today.AddDate(0, 0, 7*n))
+		default:
+			p.setDate(today.AddDate(0, n, 0))
+		}
+	}
+
+	if m := p.match(reRelativeDay); m != nil && !p.hasDate {
+		switch {
+		case strings.HasPrefix(m[1], "depois"):
+			p.setDate(today.AddDate(0, 0, 2))
+		case m[1] == "amanha":
+			p.setDate(today.AddDate(0, 0, 1))
+		default:
+			p.setDate(today)
+		}
+	}
+
+	if m := p.match(reMonthDate); m != nil && !p.hasDate {
+		day, _ := strconv.Atoi(m[1])
+		year := 0
+		if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+		}
+		if err := p.setCalendarDate(year, monthNames[m[2]], day); err != nil {
+			return err
+		}
+	}
+
+	if m := p.match(reNumericDate); m != nil && !p.hasDate {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year := 0
+		if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+			if year < 100 {
+				year += 2000
+			}
+		}
+		if month < 1 || month > 12 {
+			return fmt.Errorf("%w: month %d", ErrInvalidDateTime, month)
+		}
+		if err := p.setCalendarDate(year, time.Month(month), day); err != nil {
+			return err
+		}
+	}
+
+	if m := p.match(reDayOfMonth); m != nil && !p.hasDate {
+		day, _ := strconv.Atoi(m[1])
+		if day < 1 || day > 31 {
+			return fmt.Errorf("%w: day %d", ErrInvalidDateTime, day)
+		}
+		// Current month if still ahead, else next month with that day
+		d := today
+		for i := 0; i < 12; i++ {
+			candidate := time.Date(d.Year(), d.Month(), day, 0, 0, 0, 0, p.loc)
+			if candidate.Day() == day && !candidate.Before(today) {
+				p.setDate(candidate)
+				break
+			}
+			d = time.Date(d.Year(), d.Month()+1, 1, 0, 0, 0, 0, p.loc)
+		}
+	}
+
+	if m := p.match(reWeekday); m != nil {
+		wd := weekdayNames[m[2]]
+		if p.hasDate {
+			if p.date.Weekday() != wd {
+				p.flag(AmbiguityConflict)
+			}
+		} else {
+			// "esta/essa sexta" may be today; otherwise next occurrence
+			offset := (int(wd) - int(today.Weekday()) + 7) % 7
+			thisWeek := strings.HasPrefix(m[1], "est") || strings.HasPrefix(m[1], "ess") ||
+				strings.HasPrefix(m[1], "nest") || strings.HasPrefix(m[1], "ness")
+			if offset == 0 && !thisWeek {
+				offset = 7
+			}
+			// "proxima sexta"/"sexta que vem" skips this week's sexta
+			if (strings.HasPrefix(m[1], "proxim") || m[3] != "") && offset < daysToNextMonday(today) {
+				offset += 7
+				p.flag(AmbiguityNextWeek)
+			}
+			p.setDate(today.AddDate(0, 0, offset))
+		}
+	}
+
+	if p.match(reVagueWeek) != nil && !p.hasDate {
+		p.setDate(today.AddDate(0, 0, daysToNextMonday(today)))
+		p.flag(AmbiguityVagueDate)
+	}
+
+	if p.match(reVagueMonth) != nil && !p.hasDate {
+		p.setDate(time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, p.loc))
+		p.flag(AmbiguityVagueDate)
+	}
+
+	return nil
+}
+
+// parseTime applies time rules; period may adjust hour.
+func (p *dtParser) parseTime() error {
+	if m := p.match(rePeriod); m != nil {
+		switch m[1] {
+		case "manha":
+			p.period = PeriodMorning
+		case "tarde":
+			p.period = PeriodAfternoon
+		default:
+			p.period = PeriodEvening
+		}
+	}
+
+	explicit := false // hour written in 24h form or with minutes
+	switch {
+	case p.matchInto(reNoon, func(m []string) {
+		p.hour = 12
+		if strings.HasPrefix(m[1], "meia") {
+			p.hour = 0
+		}
+		p.minute = minutesWord(m[2])
+		explicit = true
+	}):
+	case p.matchInto(reClock, func(m []string) {
+		p.hour, _ = strconv.Atoi(m[1])
+		p.minute, _ = strconv.Atoi(m[2])
+	}):
+	case p.matchInto(reHourUnit, func(m []string) {
+		p.hour, _ = strconv.Atoi(m[1])
+		p.minute = minutesWord(m[2])
+	}):
+	case p.matchInto(reHourPrep, func(m []string) {
+		p.hour, _ = parseNumber(m[1])
+		p.minute = minutesWord(m[2])
+	}):
+	default:
+		return nil
+	}
+
+	if p.hour > 23 || p.minute > 59 {
+		return fmt.Errorf("%w: %02d:%02d", ErrInvalidDateTime, p.hour, p.minute)
+	}
+	p.hasTime = true
+
+	if explicit || p.hour == 0 || p.hour >= 12 {
+		return nil
+	}
+
+	switch p.period {
+	case PeriodAfternoon, PeriodEvening:
+		p.hour += 12
+	case PeriodMorning:
+	default:
+		// Bare "às 3": assume clinic hours (afternoon for 1..6)
+		if p.hour <= 7 {
+			p.flag(AmbiguityMeridiem)
+			if p.hour <= 6 {
+				p.hour += 12
+			}
+		}
+	}
+	return nil
+}
+
+// match finds first match of re, blanks it from text and returns submatches.
+func (p *dtParser) match(re *regexp.Regexp) []string {
+	loc := re.FindStringSubmatchIndex(p.text)
+	if loc == nil {
+		return nil
+	}
+
+	m := make([]string, len(loc)/2)
+	for i := range m {
+		if loc[2*i] >= 0 {
+			m[i] = p.text[loc[2*i]:loc[2*i+1]]
+		}
+	}
+
+	p.text = p.text[:loc[0]] + strings.Repeat(" ", loc[1]-loc[0]) + p.text[loc[1]:]
+	return m
+}
+
+// matchInto calls fn with submatches if re matches.
+func (p *dtParser) matchInto(re *regexp.Regexp, fn func([]string)) bool {
+	m := p.match(re)
+	if m == nil {
+		return false
+	}
+	fn(m)
+	return true
+}
+
+// setDate records resolved day.
+func (p *dtParser) setDate(d time.Time) {
+	p.date = d
+	p.hasDate = true
+}
+
+// setCalendarDate records explicit day/month; year 0 means next occurrence.
+func (p *dtParser) setCalendarDate(year int, month time.Month, day int) error {
+	today := midnight(p.ref)
+	y := year
+	if y == 0 {
+		y = today.Year()
+	}
+
+	d := time.Date(y, month, day, 0, 0, 0, 0, p.loc)
+	if d.Day() != day || d.Month() != month {
+		return fmt.Errorf("%w: %02d/%02d", ErrInvalidDateTime, day, month)
+	}
+	if year == 0 && d.Before(today) {
+		d = time.Date(y+1, month, day, 0, 0, 0, 0, p.loc)
+		if d.Day() != day {
+			return fmt.Errorf("%w: %02d/%02d", ErrInvalidDateTime, day, month)
+		}
+	}
+
+	p.setDate(d)
+	return nil
+}
+
+// flag records ambiguity once.
+func (p *dtParser) flag(a Ambiguity) {
+	for _, x := range p.ambiguities {
+		if x == a {
+			return
+		}
+	}
+	p.ambiguities = append(p.ambiguities, a)
+}
+
+// parseNumber parses digits or spelled-out number.
+func parseNumber(s string) (int, error) {
+	if n, ok := numberWords[s]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: number %q", ErrInvalidDateTime, s)
+	}
+	return n, nil
+}
+
+// minutesWord converts "meia"/"quinze"/digits suffix into minutes.
+func minutesWord(s string) int {
+	switch s {
+	case "":
+		return 0
+	case "meia":
+		return 30
+	case "quinze":
+		return 15
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// daysToNextMonday returns days from day to the Monday starting next week (1..7).
+func daysToNextMonday(day time.Time) int {
+	offset := (int(time.Monday) - int(day.Weekday()) + 7) % 7
+	if offset == 0 {
+		offset = 7
+	}
+	return offset
+}
+
+// midnight returns start of t's day in t's location.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// at returns day at hour:minute.
+func at(day time.Time, hour, minute int) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, hour, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,228 @@
+package nlp
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func clinicLocation(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestParseDateTime(t *testing.T) {
+	loc := clinicLocation(t)
+	// Wednesday
+	ref := time.Date(2025, time.October, 15, 10, 0, 0, 0, loc)
+	day := func(month time.Month, d, hour, minute int) time.Time {
+		return time.Date(2025, month, d, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		text        string
+		want        time.Time
+		hasDate     bool
+		hasTime     bool
+		ambiguities []Ambiguity
+	}{
+		// Relative days
+		{"amanhã às 14h", day(time.October, 16, 14, 0), true, true, nil},
+		{"Amanhã 14:30", day(time.October, 16, 14, 30), true, true, nil},
+		{"amanhã às duas da tarde", day(time.October, 16, 14, 0), true, true, nil},
+		{"quero marcar pra amanhã às 10 horas", day(time.October, 16, 10, 0), true, true, nil},
+		{"hoje às 17h", day(time.October, 15, 17, 0), true, true, nil},
+		{"hoje às 9", day(time.October, 15, 9, 0), true, true, []Ambiguity{AmbiguityPast}},
+		{"hoje", day(time.October, 15, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"depois de amanhã de manhã", day(time.October, 17, 9, 0), true, false, []Ambiguity{AmbiguityPeriodOnly}},
+		{"depois de amanha a noite", day(time.October, 17, 19, 0), true, false, []Ambiguity{AmbiguityPeriodOnly}},
+		{"amanhã às 8 da noite", day(time.October, 16, 20, 0), true, true, nil},
+
+		// Weekdays
+		{"sexta às 10h", day(time.October, 17, 10, 0), true, true, nil},
+		{"sexta-feira às 16:30", day(time.October, 17, 16, 30), true, true, nil},
+		{"sábado de manhã", day(time.October, 18, 9, 0), true, false, []Ambiguity{AmbiguityPeriodOnly}},
+		{"domingo", day(time.October, 19, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"segunda às 15h", day(time.October, 20, 15, 0), true, true, nil},
+		{"terça à tarde", day(time.October, 21, 14, 0), true, false, []Ambiguity{AmbiguityPeriodOnly}},
+		{"quarta", day(time.October, 22, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"nesta quarta às 17h", day(time.October, 15, 17, 0), true, true, nil},
+		{"essa sexta às 11h", day(time.October, 17, 11, 0), true, true, nil},
+
+		// "próxima"/"que vem" on days still in this week: following week
+		{"próxima quinta", day(time.October, 23, 0, 0), true, false, []Ambiguity{AmbiguityNextWeek, AmbiguityNoTime}},
+		{"quinta-feira que vem", day(time.October, 23, 0, 0), true, false, []Ambiguity{AmbiguityNextWeek, AmbiguityNoTime}},
+		{"próxima sexta de manhã", day(time.October, 24, 9, 0), true, false, []Ambiguity{AmbiguityNextWeek, AmbiguityPeriodOnly}},
+		{"sexta que vem às 14h", day(time.October, 24, 14, 0), true, true, []Ambiguity{AmbiguityNextWeek}},
+		{"proximo sabado as 9h", day(time.October, 25, 9, 0), true, true, []Ambiguity{AmbiguityNextWeek}},
+		{"domingo que vem", day(time.October, 26, 0, 0), true, false, []Ambiguity{AmbiguityNextWeek, AmbiguityNoTime}},
+		// ... and on days already in the following week: unchanged
+		{"próxima segunda às 15h", day(time.October, 20, 15, 0), true, true, nil},
+		{"segunda que vem às 8h", day(time.October, 20, 8, 0), true, true, nil},
+		{"próxima terça", day(time.October, 21, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"próxima quarta às 10h", day(time.October, 22, 10, 0), true, true, nil},
+
+		// Day of month and calendar dates
+		{"dia 3 às 9 e meia", day(time.November, 3, 9, 30), true, true, nil},
+		{"dia 20 às 3 da tarde", day(time.October, 20, 15, 0), true, true, nil},
+		{"dia 15 às 11h", day(time.October, 15, 11, 0), true, true, nil},
+		{"dia 31", day(time.October, 31, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"25/12 às 10h", day(time.December, 25, 10, 0), true, true, nil},
+		{"10/10", time.Date(2026, time.October, 10, 0, 0, 0, 0, loc), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"20/10/2025 às 13h", day(time.October, 20, 13, 0), true, true, nil},
+		{"02/03/26", time.Date(2026, time.March, 2, 0, 0, 0, 0, loc), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"01/10/2025 às 9h", day(time.October, 1, 9, 0), true, true, []Ambiguity{AmbiguityPast}},
+		{"5 de novembro às 14:00", day(time.November, 5, 14, 0), true, true, nil},
+		{"dia 7 de março", time.Date(2026, time.March, 7, 0, 0, 0, 0, loc), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"12 de outubro de 2026 às 10h", time.Date(2026, time.October, 12, 10, 0, 0, 0, loc), true, true, nil},
+		{"sexta dia 17 às 10h", day(time.October, 17, 10, 0), true, true, nil},
+		{"sexta dia 3", day(time.November, 3, 0, 0), true, false, []Ambiguity{AmbiguityConflict, AmbiguityNoTime}},
+
+		// Relative spans
+		{"daqui a duas semanas", day(time.October, 29, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"daqui a 3 dias às 16h", day(time.October, 18, 16, 0), true, true, nil},
+		{"em um mês", day(time.November, 15, 0, 0), true, false, []Ambiguity{AmbiguityNoTime}},
+		{"daqui a 2 horas", day(time.October, 15, 12, 0), true, true, nil},
+		{"em 30 minutos", day(time.October, 15, 10, 30), true, true, nil},
+		{"daqui meia hora", day(time.October, 15, 10, 30), true, true, nil},
+		{"semana que vem", day(time.October, 20, 0, 0), true, false, []Ambiguity{AmbiguityVagueDate, AmbiguityNoTime}},
+		{"próxima semana de tarde", day(time.October, 20, 14, 0), true, false, []Ambiguity{AmbiguityVagueDate, AmbiguityPeriodOnly}},
+		{"mês que vem à tarde", day(time.November, 1, 14, 0), true, false, []Ambiguity{AmbiguityVagueDate, AmbiguityPeriodOnly}},
+
+		// Time only
+		{"às 3", day(time.October, 15, 15, 0), false, true, []Ambiguity{AmbiguityMeridiem, AmbiguityNoDate}},
+		{"às 7", day(time.October, 16, 7, 0), false, true, []Ambiguity{AmbiguityMeridiem, AmbiguityNoDate}},
+		{"às 8", day(time.October, 16, 8, 0), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"pode ser às 11?", day(time.October, 15, 11, 0), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"as 3 da tarde", day(time.October, 15, 15, 0), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"14h", day(time.October, 15, 14, 0), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"9:15", day(time.October, 16, 9, 15), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"meio-dia", day(time.October, 15, 12, 0), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"meio dia e meia", day(time.October, 15, 12, 30), false, true, []Ambiguity{AmbiguityNoDate}},
+		{"de tarde", day(time.October, 15, 14, 0), false, false, []Ambiguity{AmbiguityPeriodOnly, AmbiguityNoDate}},
+		{"de manhã", day(time.October, 16, 9, 0), false, false, []Ambiguity{AmbiguityPeriodOnly, AmbiguityNoDate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseDateTime(tt.text, ref, loc)
+			if err != nil {
+				t.Fatalf("ParseDateTime(%q) error: %v", tt.text, err)
+			}
+			if !got.Time.Equal(tt.want) {
+				t.Errorf("Time = %v, want %v", got.Time, tt.want)
+			}
+			if got.Time.Location() != loc {
+				t.Errorf("Location = %v, want %v", got.Time.Location(), loc)
+			}
+			if got.HasDate != tt.hasDate || got.HasTime != tt.hasTime {
+				t.Errorf("HasDate, HasTime = %v, %v, want %v, %v", got.HasDate, got.HasTime, tt.hasDate, tt.hasTime)
+			}
+			if !slices.Equal(got.Ambiguities, tt.ambiguities) {
+				t.Errorf("Ambiguities = %v, want %v", got.Ambiguities, tt.ambiguities)
+			}
+			if got.Ambiguous() != (len(tt.ambiguities) > 0) {
+				t.Errorf("Ambiguous() = %v", got.Ambiguous())
+			}
+		})
+	}
+}
+
+func TestParseDateTimeErrors(t *testing.T) {
+	loc := clinicLocation(t)
+	ref := time.Date(2025, time.October, 15, 10, 0, 0, 0, loc)
+
+	tests := []struct {
+		text string
+		want error
+	}{
+		{"oi tudo bem", ErrNoDateTime},
+		{"quero marcar uma consulta", ErrNoDateTime},
+		{"", ErrNoDateTime},
+		{"31/02", ErrInvalidDateTime},
+		{"13/13", ErrInvalidDateTime},
+		{"29/02/2026", ErrInvalidDateTime},
+		{"31 de abril", ErrInvalidDateTime},
+		{"dia 32", ErrInvalidDateTime},
+		{"dia 0", ErrInvalidDateTime},
+		{"às 25h", ErrInvalidDateTime},
+		{"amanhã 10:75", ErrInvalidDateTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseDateTime(tt.text, ref, loc)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ParseDateTime(%q) = %+v, %v, want error %v", tt.text, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseDateTimeWeekOf checks "próxima"/"que vem" from each day of the week.
+func TestParseDateTimeWeekOf(t *testing.T) {
+	loc := clinicLocation(t)
+
+	tests := []struct {
+		ref      time.Time
+		text     string
+		want     time.Time
+		nextWeek bool
+	}{
+		// Monday: whole week still ahead
+		{time.Date(2025, time.October, 13, 9, 0, 0, 0, loc), "próxima sexta", time.Date(2025, time.October, 24, 0, 0, 0, 0, loc), true},
+		{time.Date(2025, time.October, 13, 9, 0, 0, 0, loc), "sexta", time.Date(2025, time.October, 17, 0, 0, 0, 0, loc), false},
+		{time.Date(2025, time.October, 13, 9, 0, 0, 0, loc), "segunda que vem", time.Date(2025, time.October, 20, 0, 0, 0, 0, loc), false},
+		// Friday: "sexta que vem" is a week ahead, not today
+		{time.Date(2025, time.October, 17, 9, 0, 0, 0, loc), "sexta que vem", time.Date(2025, time.October, 24, 0, 0, 0, 0, loc), false},
+		{time.Date(2025, time.October, 17, 9, 0, 0, 0, loc), "sábado que vem", time.Date(2025, time.October, 25, 0, 0, 0, 0, loc), true},
+		// Sunday: next Monday already starts the following week
+		{time.Date(2025, time.October, 19, 9, 0, 0, 0, loc), "próxima segunda", time.Date(2025, time.October, 20, 0, 0, 0, 0, loc), false},
+		{time.Date(2025, time.October, 19, 9, 0, 0, 0, loc), "semana que vem", time.Date(2025, time.October, 20, 0, 0, 0, 0, loc), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref.Weekday().String()+" "+tt.text, func(t *testing.T) {
+			got, err := ParseDateTime(tt.text, tt.ref, loc)
+			if err != nil {
+				t.Fatalf("ParseDateTime(%q) error: %v", tt.text, err)
+			}
+			if !got.Time.Equal(tt.want) {
+				t.Errorf("Time = %v, want %v", got.Time, tt.want)
+			}
+			if got.Has(AmbiguityNextWeek) != tt.nextWeek {
+				t.Errorf("Ambiguities = %v, want next_week %v", got.Ambiguities, tt.nextWeek)
+			}
+		})
+	}
+}
+
+// TestParseDateTimeClinicTimezone checks days are resolved in the clinic's
+// timezone, not the reference time's.
+func TestParseDateTimeClinicTimezone(t *testing.T) {
+	loc := clinicLocation(t)
+	// 01:30 UTC on the 16th is still 22:30 on the 15th in São Paulo
+	ref := time.Date(2025, time.October, 16, 1, 30, 0, 0, time.UTC)
+
+	got, err := ParseDateTime("amanhã às 9h", ref, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, time.October, 16, 9, 0, 0, 0, loc)
+	if !got.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", got.Time, want)
+	}
+
+	got, err = ParseDateTime("às 8", ref, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Time.Equal(want.Add(-time.Hour)) {
+		t.Errorf("Time = %v, want %v", got.Time, want.Add(-time.Hour))
+	}
+}
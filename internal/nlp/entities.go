@@ -0,0 +1,260 @@
+package nlp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/matheusmassa1/clara/internal/config"
+)
+
+// EntityType identifies kind of extracted entity.
+type EntityType string
+
+// Entity type constants
+const (
+	EntityPerson EntityType = "person"
+	EntityDate   EntityType = "date"
+	EntityTime   EntityType = "time"
+	EntityPhone  EntityType = "phone"
+)
+
+// Entity is a typed span found in message text.
+// Start/End are character (rune) offsets into the original text, End exclusive.
+type Entity struct {
+	Type       EntityType
+	Text       string
+	Start      int
+	End        int
+	Confidence float64 // 0..1
+}
+
+// EntityExtractor extracts entities from message text.
+type EntityExtractor interface {
+	Extract(ctx context.Context, text string) ([]Entity, error)
+}
+
+// nerLabels maps NER model entity groups to entity types.
+// LeNER-Br uses PESSOA/TEMPO; generic models use PER/DATE/TIME.
+var nerLabels = map[string]EntityType{
+	"PESSOA": EntityPerson,
+	"PER":    EntityPerson,
+	"PERSON": EntityPerson,
+	"TEMPO":  EntityDate,
+	"DATA":   EntityDate,
+	"DATE":   EntityDate,
+	"HORA":   EntityTime,
+	"TIME":   EntityTime,
+}
+
+// HFExtractor implements EntityExtractor using a Hugging Face
+// token-classification model, supplemented by RuleExtractor for
+// spans the model does not tag (times, phones).
+type HFExtractor struct {
+	client *hfClient
+	model  string
+	rules  *RuleExtractor
+}
+
+// nerPrediction is a single aggregated token-classification prediction.
+type nerPrediction struct {
+	EntityGroup string  `json:"entity_group"`
+	Score       float64 `json:"score"`
+	Word        string  `json:"word"`
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+}
+
+// NewHFExtractor creates extractor for cfg.HFNERModel.
+func NewHFExtractor(cfg *config.Config) *HFExtractor {
+	timeout := time.Duration(cfg.HFTimeout) * time.Second
+	return &HFExtractor{
+		client: newHFClient(cfg.HFAPIURL, cfg.HFAPIKey, timeout, cfg.HFMaxRetries),
+		model:  cfg.HFNERModel,
+		rules:  NewRuleExtractor(),
+	}
+}
+
+// Extract calls the NER model and merges its entities with rule matches.
+// Overlapping entities of the same type keep the longer span.
+func (e *HFExtractor) Extract(ctx context.Context, text string) ([]Entity, error) {
+	payload := map[string]any{
+		"inputs":     text,
+		"parameters": map[string]string{"aggregation_strategy": "simple"},
+	}
+
+	var predictions []nerPrediction
+	if err := e.client.infer(ctx, e.model, payload, &predictions); err != nil {
+		return nil, fmt.Errorf("failed to extract entities: %w", err)
+	}
+
+	runes := []rune(text)
+	var entities []Entity
+	for _, p := range predictions {
+		typ, ok := nerLabels[strings.ToUpper(p.EntityGroup)]
+		if !ok {
+			continue
+		}
+		if p.Start < 0 || p.End > len(runes) || p.Start >= p.End {
+			continue
+		}
+		entities = append(entities, Entity{
+			Type:       typ,
+			Text:       string(runes[p.Start:p.End]),
+			Start:      p.Start,
+			End:        p.End,
+			Confidence: p.Score,
+		})
+	}
+
+	ruleEntities, _ := e.rules.Extract(ctx, text)
+	return mergeEntities(append(entities, ruleEntities...)), nil
+}
+
+// RuleExtractor implements EntityExtractor with Portuguese regex rules.
+// Works offline; covers dates, times, phones and names after honorifics or
+// introductions.
+type RuleExtractor struct {
+	rules []entityRule
+}
+
+// entityRule matches one entity type. Span is capture group 1 if present.
+type entityRule struct {
+	typ        EntityType
+	re         *regexp.Regexp
+	confidence float64
+}
+
+// Portuguese expression fragments shared with date/time parsing.
+const (
+	weekdayPattern = `segunda(?:[- ]feira)?|ter[çc]a(?:[- ]feira)?|quarta(?:[- ]feira)?|quinta(?:[- ]feira)?|sexta(?:[- ]feira)?|s[áa]bado|domingo`
+	monthPattern   = `janeiro|fevereiro|mar[çc]o|abril|maio|junho|julho|agosto|setembro|outubro|novembro|dezembro`
+	numberPattern  = `\d{1,2}|uma?|dois|duas|tr[êe]s|quatro|cinco|seis|sete|oito|nove|dez|onze|doze`
+)
+
+// NewRuleExtractor creates rule-based extractor.
+func NewRuleExtractor() *RuleExtractor {
+	return &RuleExtractor{rules: []entityRule{
+		// Dates
+		{EntityDate, regexp.MustCompile(`(?i)depois\s+de\s+amanh[ãa]|amanh[ãa]|hoje`), 0.95},
+		{EntityDate, regexp.MustCompile(`(?i)(?:(?:na|no|nesta|neste|nessa|nesse|esta|este|essa|esse|pr[óo]xim[ao])\s+)?(?:` + weekdayPattern + `)(?:\s+que\s+vem)?`), 0.9},
+		{EntityDate, regexp.MustCompile(`(?i)(?:dia\s+)?\d{1,2}\s+de\s+(?:` + monthPattern + `)(?:\s+de\s+\d{4})?`), 0.95},
+		{EntityDate, regexp.MustCompile(`(?i)dia\s+\d{1,2}`), 0.85},
+		{EntityDate, regexp.MustCompile(`\d{1,2}/\d{1,2}(?:/\d{2,4})?`), 0.9},
+		{EntityDate, regexp.MustCompile(`(?i)(?:daqui\s+a|em)\s+(?:` + numberPattern + `)\s+(?:dias?|semanas?|m[êe]s|meses)`), 0.9},
+		{EntityDate, regexp.MustCompile(`(?i)(?:(?:na|no)\s+)?(?:pr[óo]xim[ao]\s+(?:semana|m[êe]s)|(?:semana|m[êe]s)\s+que\s+vem)`), 0.85},
+
+		// Times
+		{EntityTime, regexp.MustCompile(`(?i)(?:(?:[àa]s|das|at[ée]\s+[àa]s)\s+)?(?:\d{1,2}:\d{2}|\d{1,2}\s*(?:h|hs|hr|hrs|horas?)(?:\s*\d{2})?)(?:\s+e\s+meia)?`), 0.9},
+		{EntityTime, regexp.MustCompile(`(?i)(?:[àa]s|das)\s+(?:` + numberPattern + `)(?:\s+e\s+(?:meia|quinze|\d{1,2}))?`), 0.8},
+		{EntityTime, regexp.MustCompile(`(?i)(?:ao\s+)?meio[- ]dia(?:\s+e\s+meia)?|meia[- ]noite`), 0.9},
+		{EntityTime, regexp.MustCompile(`(?i)(?:de|pela|[àa]|na|no)\s+(?:manh[ãa]|tarde|noite)`), 0.75},
+
+		// Phones: optional +55, area code, 8-9 digit number
+		{EntityPhone, regexp.MustCompile(`(?:\+?55\s?)?\(?\d{2}\)?\s?9?\d{4}[-\s]?\d{4}`), 0.9},
+
+		// Names after honorifics (span is the name only)
+		{EntityPerson, regexp.MustCompile(`(?:Dr|Dra|Doutor|Doutora|Sr|Sra|Senhor|Senhora)\.?\s+(\p{Lu}\p{L}+(?:\s+(?:d[aeo]s?\s+)?\p{Lu}\p{L}+)*)`), 0.8},
+		// Names after introductions ("meu nome é", "me chamo", "sou a")
+		{EntityPerson, regexp.MustCompile(`(?i:meu\s+nome\s+[ée]|me\s+chamo|sou\s+[ao])\s+(\p{Lu}\p{L}+(?:\s+(?:d[aeo]s?\s+)?\p{Lu}\p{L}+)*)`), 0.7},
+	}}
+}
+
+// Extract matches all rules against text. Never returns an error.
+func (e *RuleExtractor) Extract(ctx context.Context, text string) ([]Entity, error) {
+	var entities []Entity
+	for _, rule := range e.rules {
+		for _, span := range findSpans(rule.re, text) {
+			start, end := span[0], span[1]
+			entities = append(entities, Entity{
+				Type:       rule.typ,
+				Text:       text[start:end],
+				Start:      utf8.RuneCountInString(text[:start]),
+				End:        utf8.RuneCountInString(text[:end]),
+				Confidence: rule.confidence,
+			})
+		}
+	}
+	return mergeEntities(entities), nil
+}
+
+// findSpans returns byte spans of non-overlapping matches that sit on word
+// boundaries. Span is capture group 1 if present. A match glued to a word
+// (e.g. "na" inside "Ana") is retried one rune later.
+func findSpans(re *regexp.Regexp, text string) [][2]int {
+	var spans [][2]int
+	for pos := 0; pos < len(text); {
+		m := re.FindStringSubmatchIndex(text[pos:])
+		if m == nil {
+			break
+		}
+
+		start, end := pos+m[0], pos+m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = pos+m[2], pos+m[3]
+		}
+
+		if end > start && isWordBoundary(text, start, end) {
+			spans = append(spans, [2]int{start, end})
+			pos = pos + m[1]
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[pos+m[0]:])
+		pos = pos + m[0] + size
+	}
+	return spans
+}
+
+// isWordBoundary checks byte span is not glued to surrounding letters/digits.
+func isWordBoundary(text string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(text[:start])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[end:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeEntities drops entities overlapping a longer (or equally long but
+// more confident) entity of the same type, and sorts by position.
+func mergeEntities(entities []Entity) []Entity {
+	sort.SliceStable(entities, func(i, j int) bool {
+		li, lj := entities[i].End-entities[i].Start, entities[j].End-entities[j].Start
+		if li != lj {
+			return li > lj
+		}
+		return entities[i].Confidence > entities[j].Confidence
+	})
+
+	var kept []Entity
+	for _, ent := range entities {
+		overlaps := false
+		for _, k := range kept {
+			if k.Type == ent.Type && ent.Start < k.End && k.Start < ent.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, ent)
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Start < kept[j].Start
+	})
+	return kept
+}
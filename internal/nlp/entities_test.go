@@ -0,0 +1,214 @@
+package nlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/nlp/nlptest"
+)
+
+const testNERModel = "clara/ner"
+
+// span renders entity as "type:text@start-end" for comparison.
+func span(e Entity) string {
+	return fmt.Sprintf("%s:%s@%d-%d", e.Type, e.Text, e.Start, e.End)
+}
+
+func spans(entities []Entity) []string {
+	out := make([]string, len(entities))
+	for i, e := range entities {
+		out[i] = span(e)
+	}
+	return out
+}
+
+// checkRuneOffsets fails unless every entity's offsets select its text.
+func checkRuneOffsets(t *testing.T, text string, entities []Entity) {
+	t.Helper()
+	runes := []rune(text)
+	for _, e := range entities {
+		if e.Start < 0 || e.End > len(runes) || string(runes[e.Start:e.End]) != e.Text {
+			t.Errorf("%q: offsets of %s do not select its text", text, span(e))
+		}
+	}
+}
+
+func TestRuleExtractor(t *testing.T) {
+	e := NewRuleExtractor()
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"quero marcar com a Dra. Ana terça às 15h",
+			[]string{"person:Ana@24-27", "date:terça@28-33", "time:às 15h@34-40"}},
+		{"na próxima segunda às 14:30 com o Dr. João da Silva",
+			[]string{"date:próxima segunda@3-18", "time:às 14:30@19-27", "person:João da Silva@38-51"}},
+		{"pode ser dia 20 de outubro às 9 e meia?",
+			[]string{"date:dia 20 de outubro@9-26", "time:às 9 e meia@27-38"}},
+		{"Ana, amanhã de manhã", []string{"date:amanhã@5-11", "time:de manhã@12-20"}},
+		{"depois de amanhã ao meio-dia", []string{"date:depois de amanhã@0-16", "time:ao meio-dia@17-28"}},
+		{"daqui a duas semanas, 20/10 ou 21/10/2025",
+			[]string{"date:daqui a duas semanas@0-20", "date:20/10@22-27", "date:21/10/2025@31-41"}},
+		{"na semana que vem, às 10h30", []string{"date:na semana que vem@0-17", "time:às 10h30@19-27"}},
+		{"meu número é (11) 98765-4321", []string{"phone:(11) 98765-4321@13-28"}},
+		{"+55 11 987654321", []string{"phone:+55 11 987654321@0-16"}},
+		{"Meu nome é Maria da Silva", []string{"person:Maria da Silva@11-25"}},
+		{"me chamo João", []string{"person:João@9-13"}},
+		// Lowercase words after honorifics and introductions are not names
+		{"sou a favor, sr. ninguém", nil},
+		// Fragments inside words are not matched
+		{"Joana hojeira", nil},
+		{"quero cancelar", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := e.Extract(context.Background(), tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(spans(got)) != fmt.Sprint(tt.want) {
+				t.Errorf("Extract(%q) = %v, want %v", tt.text, spans(got), tt.want)
+			}
+			checkRuneOffsets(t, tt.text, got)
+			for _, ent := range got {
+				if ent.Confidence <= 0 || ent.Confidence > 1 {
+					t.Errorf("Extract(%q): %s has confidence %v", tt.text, span(ent), ent.Confidence)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeEntitiesKeepsLongerSpan(t *testing.T) {
+	got := mergeEntities([]Entity{
+		{Type: EntityTime, Text: "15h", Start: 3, End: 6, Confidence: 0.99},
+		{Type: EntityTime, Text: "às 15h", Start: 0, End: 6, Confidence: 0.8},
+		{Type: EntityDate, Text: "às", Start: 0, End: 2, Confidence: 0.5}, // Other type overlaps freely
+		{Type: EntityPerson, Text: "Ana", Start: 10, End: 13, Confidence: 0.7},
+		{Type: EntityPerson, Text: "Ana", Start: 10, End: 13, Confidence: 0.9},
+	})
+	want := []string{"time:às 15h@0-6", "date:às@0-2", "person:Ana@10-13"}
+	if fmt.Sprint(spans(got)) != fmt.Sprint(want) {
+		t.Fatalf("mergeEntities = %v, want %v", spans(got), want)
+	}
+	if got[2].Confidence != 0.9 {
+		t.Errorf("equal spans: kept confidence %v, want 0.9", got[2].Confidence)
+	}
+}
+
+// newTestExtractor returns HF extractor pointed at srv.
+func newTestExtractor(srv *nlptest.Server) *HFExtractor {
+	e := NewHFExtractor(&config.Config{
+		HFAPIURL:     srv.URL + "/",
+		HFAPIKey:     "test-key",
+		HFNERModel:   testNERModel,
+		HFTimeout:    5,
+		HFMaxRetries: 1,
+	})
+	e.client.backoff = 10 * time.Millisecond
+	return e
+}
+
+func TestHFExtractor(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	e := newTestExtractor(srv)
+
+	text := "quero marcar com a Dra. Ana terça às 15h"
+	srv.SetEntities(text,
+		nlptest.Entity{EntityGroup: "PESSOA", Score: 0.98, Word: "Ana", Start: 24, End: 27},
+		nlptest.Entity{EntityGroup: "TEMPO", Score: 0.93, Word: "terça", Start: 28, End: 33},
+		nlptest.Entity{EntityGroup: "LOCAL", Score: 0.9, Word: "Dra", Start: 19, End: 22}, // Unmapped
+		nlptest.Entity{EntityGroup: "PER", Score: 0.9, Word: "?", Start: 38, End: 80},     // Out of range
+	)
+
+	got, err := e.Extract(context.Background(), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Model entities merged with rule times; rules' equal "terça" span is less confident
+	want := []string{"person:Ana@24-27", "date:terça@28-33", "time:às 15h@34-40"}
+	if fmt.Sprint(spans(got)) != fmt.Sprint(want) {
+		t.Fatalf("Extract = %v, want %v", spans(got), want)
+	}
+	if got[0].Confidence != 0.98 || got[1].Confidence != 0.93 {
+		t.Errorf("model confidences: got %v and %v, want 0.98 and 0.93", got[0].Confidence, got[1].Confidence)
+	}
+	checkRuneOffsets(t, text, got)
+
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Model != testNERModel || reqs[0].Inputs != text {
+		t.Errorf("requests: got %+v, want one to %q", reqs, testNERModel)
+	}
+}
+
+func TestHFExtractorCharacterOffsets(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	e := newTestExtractor(srv)
+
+	// Offsets count characters, not bytes: "ç" and "ã" are one each
+	text := "terça, às três, com o João Magalhães"
+	srv.SetEntities(text, nlptest.Entity{EntityGroup: "PER", Score: 0.95, Word: "João Magalhães", Start: 22, End: 36})
+
+	got, err := e.Extract(context.Background(), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"date:terça@0-5", "time:às três@7-14", "person:João Magalhães@22-36"}
+	if fmt.Sprint(spans(got)) != fmt.Sprint(want) {
+		t.Errorf("Extract = %v, want %v", spans(got), want)
+	}
+	checkRuneOffsets(t, text, got)
+}
+
+func TestHFExtractorErrors(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	e := newTestExtractor(srv)
+
+	srv.FailWith(http.StatusUnauthorized)
+	if _, err := e.Extract(context.Background(), "amanhã"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Extract unauthorized: got %v, want %v", err, ErrUnauthorized)
+	}
+
+	// Fallback serves rule entities while the model is down
+	fallback := NewFallbackExtractor(e, NewRuleExtractor())
+	got, err := fallback.Extract(context.Background(), "amanhã às 15h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"date:amanhã@0-6", "time:às 15h@7-13"}; fmt.Sprint(spans(got)) != fmt.Sprint(want) {
+		t.Errorf("fallback Extract = %v, want %v", spans(got), want)
+	}
+}
+
+func TestNewExtractor(t *testing.T) {
+	tests := []struct {
+		engine string
+		want   string
+	}{
+		{config.NLPEngineRules, "*nlp.RuleExtractor"},
+		{config.NLPEngineHF, "*nlp.HFExtractor"},
+		{config.NLPEngineHybrid, "*nlp.FallbackExtractor"},
+	}
+	for _, tt := range tests {
+		e, err := NewExtractor(&config.Config{NLPEngine: tt.engine})
+		if err != nil {
+			t.Fatalf("NewExtractor(%q): %v", tt.engine, err)
+		}
+		if got := fmt.Sprintf("%T", e); got != tt.want {
+			t.Errorf("NewExtractor(%q) = %s, want %s", tt.engine, got, tt.want)
+		}
+	}
+	if _, err := NewExtractor(&config.Config{NLPEngine: "spacy"}); err == nil {
+		t.Error("NewExtractor(unknown engine): expected error")
+	}
+}
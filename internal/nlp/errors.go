@@ -0,0 +1,29 @@
+package nlp
+
+import "errors"
+
+// Error types for classification.
+var (
+	// ErrModelLoading indicates model still loading after all retries (transient).
+	ErrModelLoading = errors.New("model loading")
+
+	// ErrUnauthorized indicates invalid or missing API key (permanent).
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrRateLimited indicates API quota exceeded (transient).
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnexpectedResponse indicates response body could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+
+	// ErrNoDateTime indicates text carries no date or time expression.
+	ErrNoDateTime = errors.New("no date or time found")
+
+	// ErrInvalidDateTime indicates expression names an impossible date or time.
+	ErrInvalidDateTime = errors.New("invalid date or time")
+)
+
+// isRetryable checks if error is transient and request may be retried.
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrModelLoading) || errors.Is(err, ErrRateLimited)
+}
@@ -0,0 +1,109 @@
+package nlp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// FallbackClassifier tries primary classifier first and falls back to
+// secondary on error or when primary confidence is below threshold.
+type FallbackClassifier struct {
+	primary       IntentClassifier
+	secondary     IntentClassifier
+	minConfidence float64
+}
+
+// NewFallbackClassifier creates composite classifier.
+func NewFallbackClassifier(primary, secondary IntentClassifier, minConfidence float64) *FallbackClassifier {
+	return &FallbackClassifier{
+		primary:       primary,
+		secondary:     secondary,
+		minConfidence: minConfidence,
+	}
+}
+
+// Classify returns primary result when confident, otherwise secondary.
+// If both are unsure, keeps primary result (secondary found nothing).
+func (c *FallbackClassifier) Classify(ctx context.Context, text string) (*Classification, error) {
+	result, err := c.primary.Classify(ctx, text)
+	if err != nil {
+		log.Warn().Err(err).Msg("primary intent classifier failed, using fallback")
+		return c.secondary.Classify(ctx, text)
+	}
+
+	if result.Intent != IntentUnknown && result.Confidence >= c.minConfidence {
+		return result, nil
+	}
+
+	fallback, err := c.secondary.Classify(ctx, text)
+	if err != nil {
+		return result, nil
+	}
+	if fallback.Intent == IntentUnknown {
+		return result, nil
+	}
+
+	log.Debug().
+		Str("primary", string(result.Intent)).
+		Float64("primary_confidence", result.Confidence).
+		Str("fallback", string(fallback.Intent)).
+		Msg("low confidence intent, using fallback")
+	return fallback, nil
+}
+
+// NewClassifier builds intent classifier for cfg.NLPEngine.
+func NewClassifier(cfg *config.Config) (IntentClassifier, error) {
+	switch cfg.NLPEngine {
+	case config.NLPEngineRules:
+		return NewRuleClassifier(), nil
+	case config.NLPEngineHF:
+		return NewHFClassifier(cfg)
+	case config.NLPEngineHybrid:
+		hf, err := NewHFClassifier(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewFallbackClassifier(hf, NewRuleClassifier(), cfg.NLPMinConfidence), nil
+	default:
+		return nil, fmt.Errorf("unknown nlp engine %q", cfg.NLPEngine)
+	}
+}
+
+// FallbackExtractor tries primary extractor first and falls back to
+// secondary on error.
+type FallbackExtractor struct {
+	primary   EntityExtractor
+	secondary EntityExtractor
+}
+
+// NewFallbackExtractor creates composite extractor.
+func NewFallbackExtractor(primary, secondary EntityExtractor) *FallbackExtractor {
+	return &FallbackExtractor{primary: primary, secondary: secondary}
+}
+
+// Extract returns primary entities, or secondary entities if primary fails.
+func (e *FallbackExtractor) Extract(ctx context.Context, text string) ([]Entity, error) {
+	entities, err := e.primary.Extract(ctx, text)
+	if err != nil {
+		log.Warn().Err(err).Msg("primary entity extractor failed, using fallback")
+		return e.secondary.Extract(ctx, text)
+	}
+	return entities, nil
+}
+
+// NewExtractor builds entity extractor for cfg.NLPEngine.
+func NewExtractor(cfg *config.Config) (EntityExtractor, error) {
+	switch cfg.NLPEngine {
+	case config.NLPEngineRules:
+		return NewRuleExtractor(), nil
+	case config.NLPEngineHF:
+		return NewHFExtractor(cfg), nil
+	case config.NLPEngineHybrid:
+		return NewFallbackExtractor(NewHFExtractor(cfg), NewRuleExtractor()), nil
+	default:
+		return nil, fmt.Errorf("unknown nlp engine %q", cfg.NLPEngine)
+	}
+}
@@ -0,0 +1,120 @@
+package nlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/nlp/nlptest"
+)
+
+// stubClassifier returns a fixed result or error and counts calls.
+type stubClassifier struct {
+	result *Classification
+	err    error
+	calls  int
+}
+
+func (s *stubClassifier) Classify(ctx context.Context, text string) (*Classification, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func classified(intent Intent, confidence float64) *stubClassifier {
+	return &stubClassifier{result: &Classification{Intent: intent, Confidence: confidence, Label: string(intent)}}
+}
+
+func TestFallbackClassifier(t *testing.T) {
+	failing := func() *stubClassifier { return &stubClassifier{err: errors.New("model down")} }
+
+	tests := []struct {
+		name          string
+		primary       *stubClassifier
+		secondary     *stubClassifier
+		want          Intent
+		wantSecondary bool
+	}{
+		{"confident primary", classified(IntentCancel, 0.9), classified(IntentConfirm, 0.9), IntentCancel, false},
+		{"primary at threshold", classified(IntentCancel, 0.6), classified(IntentConfirm, 0.9), IntentCancel, false},
+		{"primary error", failing(), classified(IntentConfirm, 0.8), IntentConfirm, true},
+		{"low confidence", classified(IntentCancel, 0.4), classified(IntentReschedule, 0.9), IntentReschedule, true},
+		{"primary unknown", classified(IntentUnknown, 0.95), classified(IntentGreeting, 0.8), IntentGreeting, true},
+		{"secondary unknown keeps primary", classified(IntentCancel, 0.4), classified(IntentUnknown, 0), IntentCancel, true},
+		{"secondary error keeps primary", classified(IntentCancel, 0.4), failing(), IntentCancel, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFallbackClassifier(tt.primary, tt.secondary, 0.6)
+			got, err := c.Classify(context.Background(), "texto")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Intent != tt.want {
+				t.Errorf("Intent = %s, want %s", got.Intent, tt.want)
+			}
+			if asked := tt.secondary.calls > 0; asked != tt.wantSecondary {
+				t.Errorf("secondary asked = %v, want %v", asked, tt.wantSecondary)
+			}
+		})
+	}
+
+	// Both failing surfaces the secondary's error
+	c := NewFallbackClassifier(failing(), &stubClassifier{err: ErrUnauthorized}, 0.6)
+	if _, err := c.Classify(context.Background(), "texto"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("both failing: got %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestHybridClassifierFallsBackToRules(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	c := NewFallbackClassifier(newTestClassifier(t, srv), NewRuleClassifier(), 0.6)
+
+	// Model down: rules answer
+	srv.FailWith(http.StatusUnauthorized)
+	got, err := c.Classify(context.Background(), "quero desmarcar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Intent != IntentCancel || got.Label != "rule:desmarcar" {
+		t.Errorf("model down: got %s (%s), want %s from rules", got.Intent, got.Label, IntentCancel)
+	}
+}
+
+func TestNewClassifier(t *testing.T) {
+	tests := []struct {
+		engine string
+		want   string
+	}{
+		{config.NLPEngineRules, "*nlp.RuleClassifier"},
+		{config.NLPEngineHF, "*nlp.HFClassifier"},
+		{config.NLPEngineHybrid, "*nlp.FallbackClassifier"},
+	}
+	for _, tt := range tests {
+		c, err := NewClassifier(&config.Config{NLPEngine: tt.engine, NLPMinConfidence: 0.6})
+		if err != nil {
+			t.Fatalf("NewClassifier(%q): %v", tt.engine, err)
+		}
+		if got := fmt.Sprintf("%T", c); got != tt.want {
+			t.Errorf("NewClassifier(%q) = %s, want %s", tt.engine, got, tt.want)
+		}
+	}
+
+	hybrid, _ := NewClassifier(&config.Config{NLPEngine: config.NLPEngineHybrid, NLPMinConfidence: 0.7})
+	if fc := hybrid.(*FallbackClassifier); fc.minConfidence != 0.7 {
+		t.Errorf("hybrid minConfidence = %v, want 0.7", fc.minConfidence)
+	}
+
+	if _, err := NewClassifier(&config.Config{NLPEngine: "spacy"}); err == nil {
+		t.Error("NewClassifier(unknown engine): expected error")
+	}
+	for _, engine := range []string{config.NLPEngineHF, config.NLPEngineHybrid} {
+		if _, err := NewClassifier(&config.Config{NLPEngine: engine, HFIntentLabels: "LABEL_0"}); err == nil {
+			t.Errorf("NewClassifier(%q) with bad labels: expected error", engine)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package nlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxLoadingWait caps wait between retries while model is loading.
+const maxLoadingWait = 30 * time.Second
+
+// hfClient calls the Hugging Face Inference API.
+// Retries 503 "model loading" responses with backoff.
+type hfClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	maxRetries int
+	backoff    time.Duration
+}
+
+// hfError is the error body returned by the Inference API.
+type hfError struct {
+	Error         string  `json:"error"`
+	EstimatedTime float64 `json:"estimated_time"`
+}
+
+// newHFClient creates Inference API client.
+func newHFClient(baseURL, apiKey string, timeout time.Duration, maxRetries int) *hfClient {
+	return &hfClient{
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		maxRetries: maxRetries,
+		backoff:    1 * time.Second,
+	}
+}
+
+// infer posts payload to model endpoint and decodes response into out.
+func (c *hfClient) infer(ctx context.Context, model string, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	url := c.baseURL + "/" + model
+	backoff := c.backoff
+
+	for attempt := 0; ; attempt++ {
+		wait, err := c.do(ctx, url, body, out)
+		if err == nil {
+			return nil
+		}
+
+		if !isRetryable(err) || attempt >= c.maxRetries {
+			return err
+		}
+
+		// Prefer server estimate when model is loading
+		if wait <= 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if wait > maxLoadingWait {
+			wait = maxLoadingWait
+		}
+
+		log.Warn().
+			Err(err).
+			Str("model", model).
+			Int("attempt", attempt+1).
+			Dur("wait", wait).
+			Msg("inference request failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// do performs single request. Returns suggested wait for retryable errors.
+func (c *hfClient) do(ctx context.Context, url string, body []byte, out any) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("inference request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if err := json.Unmarshal(data, out); err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+		}
+		return 0, nil
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		var hfErr hfError
+		_ = json.Unmarshal(data, &hfErr)
+		wait := time.Duration(hfErr.EstimatedTime * float64(time.Second))
+		return wait, fmt.Errorf("%w: %s", ErrModelLoading, hfErr.Error)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return 0, ErrRateLimited
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return 0, ErrUnauthorized
+	default:
+		return 0, fmt.Errorf("%w: status %d: %s", ErrUnexpectedResponse, resp.StatusCode, truncate(string(data), 200))
+	}
+}
+
+// truncate shortens s to at most n bytes for logging.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
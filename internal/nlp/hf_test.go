@@ -0,0 +1,245 @@
+package nlp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/nlp/nlptest"
+)
+
+const testModel = "clara/intent"
+
+// newTestClassifier returns classifier pointed at srv, retrying twice with
+// a short backoff.
+func newTestClassifier(t *testing.T, srv *nlptest.Server) *HFClassifier {
+	t.Helper()
+	c, err := NewHFClassifier(&config.Config{
+		HFAPIURL:       srv.URL + "/",
+		HFAPIKey:       "test-key",
+		HFIntentModel:  testModel,
+		HFIntentLabels: "LABEL_0=schedule, LABEL_1=cancel",
+		HFTimeout:      5,
+		HFMaxRetries:   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.client.backoff = 10 * time.Millisecond
+	return c
+}
+
+func TestHFClassifierMapsLabels(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	c := newTestClassifier(t, srv)
+
+	tests := []struct {
+		text       string
+		labels     []nlptest.Label
+		intent     Intent
+		confidence float64
+		label      string
+	}{
+		{"quero marcar uma consulta", []nlptest.Label{{Label: "LABEL_0", Score: 0.91}, {Label: "LABEL_1", Score: 0.09}},
+			IntentSchedule, 0.91, "LABEL_0"},
+		{"preciso desmarcar", []nlptest.Label{{Label: "LABEL_0", Score: 0.2}, {Label: "LABEL_1", Score: 0.8}},
+			IntentCancel, 0.8, "LABEL_1"},
+		// Default labels, matched case-insensitively
+		{"posso trocar o horário?", []nlptest.Label{{Label: "Remarcar", Score: 0.7}, {Label: "cancelar", Score: 0.3}},
+			IntentReschedule, 0.7, "Remarcar"},
+		{"bom dia", []nlptest.Label{{Label: "saudação", Score: 0.95}}, IntentGreeting, 0.95, "saudação"},
+		{"confirmo sim", []nlptest.Label{{Label: "CONFIRM", Score: 0.66}}, IntentConfirm, 0.66, "CONFIRM"},
+		// Unmapped label keeps the model's score
+		{"qual o endereço?", []nlptest.Label{{Label: "LABEL_7", Score: 0.6}, {Label: "LABEL_0", Score: 0.4}},
+			IntentUnknown, 0.6, "LABEL_7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			srv.SetLabels(tt.text, tt.labels...)
+			got, err := c.Classify(context.Background(), tt.text)
+			if err != nil {
+				t.Fatalf("Classify(%q) error: %v", tt.text, err)
+			}
+			if got.Intent != tt.intent || got.Confidence != tt.confidence || got.Label != tt.label {
+				t.Errorf("Classify(%q) = %+v, want %s %.2f %s", tt.text, *got, tt.intent, tt.confidence, tt.label)
+			}
+		})
+	}
+
+	// Unknown inputs get the fake's default "unknown" label
+	got, err := c.Classify(context.Background(), "blá")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Intent != IntentUnknown || got.Confidence != 1 {
+		t.Errorf("Classify(default) = %+v, want unknown with score 1", *got)
+	}
+
+	for _, req := range srv.Requests() {
+		if req.Model != testModel || req.APIKey != "test-key" {
+			t.Errorf("request to %q with key %q, want %q with %q", req.Model, req.APIKey, testModel, "test-key")
+		}
+	}
+	if n := len(srv.Requests()); n != len(tests)+1 {
+		t.Errorf("requests: got %d, want %d", n, len(tests)+1)
+	}
+}
+
+func TestHFClassifierNoPredictions(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	c := newTestClassifier(t, srv)
+
+	srv.SetLabels("vazio")
+	if _, err := c.Classify(context.Background(), "vazio"); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Errorf("Classify with no predictions: got %v, want %v", err, ErrUnexpectedResponse)
+	}
+}
+
+func TestNewHFClassifierRejectsBadLabels(t *testing.T) {
+	for _, labels := range []string{"LABEL_0", "LABEL_0=schedule,LABEL_1=book"} {
+		if _, err := NewHFClassifier(&config.Config{HFIntentLabels: labels}); err == nil {
+			t.Errorf("NewHFClassifier(%q): expected error", labels)
+		}
+	}
+}
+
+func TestHFClassifierRetriesWhileModelLoads(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	c := newTestClassifier(t, srv)
+	srv.SetDefault(nlptest.Label{Label: "LABEL_0", Score: 0.9})
+
+	// Two 503s then success: within the two retries
+	srv.LoadFor(2)
+	got, err := c.Classify(context.Background(), "amanhã às 10")
+	if err != nil {
+		t.Fatalf("Classify after loading: %v", err)
+	}
+	if got.Intent != IntentSchedule {
+		t.Errorf("Classify after loading: got %s, want %s", got.Intent, IntentSchedule)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("requests: got %d, want 3", n)
+	}
+
+	// Still loading after the last retry
+	srv.LoadFor(10)
+	if _, err := c.Classify(context.Background(), "amanhã às 10"); !errors.Is(err, ErrModelLoading) {
+		t.Errorf("Classify while loading: got %v, want %v", err, ErrModelLoading)
+	}
+	if n := len(srv.Requests()); n != 6 {
+		t.Errorf("requests: got %d, want 6", n)
+	}
+}
+
+func TestHFClassifierBacksOffWhenRateLimited(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	c := newTestClassifier(t, srv)
+
+	srv.FailWith(http.StatusTooManyRequests)
+	start := time.Now()
+	_, err := c.Classify(context.Background(), "oi")
+	elapsed := time.Since(start)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Classify rate limited: got %v, want %v", err, ErrRateLimited)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("requests: got %d, want 3", n)
+	}
+	// No server estimate: waits 10ms, then 20ms
+	if elapsed < 30*time.Millisecond {
+		t.Errorf("retried after %s, want backoff of at least 30ms", elapsed)
+	}
+
+	srv.FailWith(0)
+	if _, err := c.Classify(context.Background(), "oi"); err != nil {
+		t.Errorf("Classify after limit lifted: %v", err)
+	}
+}
+
+func TestHFClassifierDoesNotRetryPermanentErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusBadRequest, ErrUnexpectedResponse},
+		{http.StatusNotFound, ErrUnexpectedResponse},
+		{http.StatusInternalServerError, ErrUnexpectedResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := nlptest.NewServer()
+			defer srv.Close()
+			c := newTestClassifier(t, srv)
+
+			srv.FailWith(tt.status)
+			if _, err := c.Classify(context.Background(), "oi"); !errors.Is(err, tt.want) {
+				t.Errorf("Classify with status %d: got %v, want %v", tt.status, err, tt.want)
+			}
+			if n := len(srv.Requests()); n != 1 {
+				t.Errorf("requests: got %d, want 1 (no retry)", n)
+			}
+		})
+	}
+}
+
+func TestHFClassifierTimeout(t *testing.T) {
+	srv := nlptest.NewServer()
+	defer srv.Close()
+	srv.Delay(time.Second)
+
+	t.Run("client timeout", func(t *testing.T) {
+		c := newTestClassifier(t, srv)
+		c.client.httpClient.Timeout = 20 * time.Millisecond
+		before := len(srv.Requests())
+
+		_, err := c.Classify(context.Background(), "oi")
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("Classify against slow server: got %v, want timeout", err)
+		}
+		if n := len(srv.Requests()) - before; n != 1 {
+			t.Errorf("requests: got %d, want 1 (no retry)", n)
+		}
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		c := newTestClassifier(t, srv)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		if _, err := c.Classify(ctx, "oi"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Classify with expired context: got %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("context deadline while backing off", func(t *testing.T) {
+		srv.Delay(0)
+		srv.FailWith(http.StatusTooManyRequests)
+		defer srv.FailWith(0)
+
+		c := newTestClassifier(t, srv)
+		c.client.backoff = time.Minute
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		if _, err := c.Classify(ctx, "oi"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Classify while backing off: got %v, want %v", err, context.DeadlineExceeded)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("gave up after %s, want as soon as the context expired", elapsed)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package nlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Intent identifies what the user wants from a message.
+type Intent string
+
+// Intent constants
+const (
+	IntentSchedule         Intent = "schedule"
+	IntentReschedule       Intent = "reschedule"
+	IntentCancel           Intent = "cancel"
+	IntentConfirm          Intent = "confirm"
+	IntentListAppointments Intent = "list_appointments"
+	IntentGreeting         Intent = "greeting"
+	IntentUnknown          Intent = "unknown"
+)
+
+// Classification is the result of intent classification.
+type Classification struct {
+	Intent     Intent
+	Confidence float64 // 0..1
+	Label      string  // Raw label reported by the classifier
+}
+
+// IntentClassifier classifies message text into an intent.
+type IntentClassifier interface {
+	Classify(ctx context.Context, text string) (*Classification, error)
+}
+
+// intents lists every known intent (for label validation).
+var intents = []Intent{
+	IntentSchedule,
+	IntentReschedule,
+	IntentCancel,
+	IntentConfirm,
+	IntentListAppointments,
+	IntentGreeting,
+	IntentUnknown,
+}
+
+// defaultLabels maps common model labels (lowercased) to intents.
+var defaultLabels = map[string]Intent{
+	"schedule":          IntentSchedule,
+	"agendar":           IntentSchedule,
+	"marcar":            IntentSchedule,
+	"reschedule":        IntentReschedule,
+	"remarcar":          IntentReschedule,
+	"reagendar":         IntentReschedule,
+	"cancel":            IntentCancel,
+	"cancelar":          IntentCancel,
+	"confirm":           IntentConfirm,
+	"confirmar":         IntentConfirm,
+	"list_appointments": IntentListAppointments,
+	"listar":            IntentListAppointments,
+	"consultar":         IntentListAppointments,
+	"greeting":          IntentGreeting,
+	"saudacao":          IntentGreeting,
+	"saudação":          IntentGreeting,
+	"unknown":           IntentUnknown,
+	"outro":             IntentUnknown,
+}
+
+// ParseLabelMap parses "label=intent" pairs separated by commas.
+// Result extends default label mapping; labels are case-insensitive.
+func ParseLabelMap(s string) (map[string]Intent, error) {
+	labels := make(map[string]Intent, len(defaultLabels))
+	for k, v := range defaultLabels {
+		labels[k] = v
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		label, intent, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label mapping %q: expected label=intent", pair)
+		}
+
+		in := Intent(strings.TrimSpace(intent))
+		if !isKnownIntent(in) {
+			return nil, fmt.Errorf("invalid label mapping %q: unknown intent %q", pair, in)
+		}
+		labels[strings.ToLower(strings.TrimSpace(label))] = in
+	}
+
+	return labels, nil
+}
+
+// isKnownIntent checks intent is one of the declared constants.
+func isKnownIntent(in Intent) bool {
+	for _, known := range intents {
+		if in == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+// Package nlptest provides a fake Hugging Face Inference API for tests.
+package nlptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Label is a text-classification prediction returned by the fake.
+type Label struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// Entity is a token-classification prediction returned by the fake.
+// Start/End are character offsets, as returned by the real API.
+type Entity struct {
+	EntityGroup string  `json:"entity_group"`
+	Score       float64 `json:"score"`
+	Word        string  `json:"word"`
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+}
+
+// Request records a call received by the fake.
+type Request struct {
+	Model  string
+	Inputs string
+	APIKey string
+}
+
+// Server is an httptest-based fake of the Inference API.
+// Point config.Config.HFAPIURL at Server.URL.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	labels   map[string][]Label
+	entities map[string][]Entity
+	fallback []Label
+	loading  int
+	status   int
+	delay    time.Duration
+	requests []Request
+}
+
+// NewServer starts fake server. Unknown inputs are classified "unknown" (score 1).
+// Caller must Close it.
+func NewServer() *Server {
+	s := &Server{
+		labels:   make(map[string][]Label),
+		entities: make(map[string][]Entity),
+		fallback: []Label{{Label: "unknown", Score: 1}},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// SetLabels sets predictions returned for exact input text.
+func (s *Server) SetLabels(text string, labels ...Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labels[text] = labels
+}
+
+// SetEntities sets entities returned for exact input text (NER requests).
+// Inputs without explicit entities yield an empty list.
+func (s *Server) SetEntities(text string, entities ...Entity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entities[text] = entities
+}
+
+// SetDefault sets predictions returned for inputs without explicit labels.
+func (s *Server) SetDefault(labels ...Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = labels
+}
+
+// LoadFor makes the next n requests answer 503 "model loading".
+func (s *Server) LoadFor(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loading = n
+}
+
+// FailWith makes every request answer with status code (0 restores normal behavior).
+func (s *Server) FailWith(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// Delay makes every response wait d, or until the client gives up (0 restores).
+func (s *Server) Delay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// Requests returns calls received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// serve handles POST /{model}.
+// Requests carrying parameters are answered as token classification.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Inputs     string         `json:"inputs"`
+		Parameters map[string]any `json:"parameters"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, `{"error":"invalid body"}`, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Model:  strings.TrimPrefix(r.URL.Path, "/"),
+		Inputs: body.Inputs,
+		APIKey: strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+	})
+
+	status := s.status
+	loading := s.loading > 0
+	if loading {
+		s.loading--
+	}
+
+	labels, ok := s.labels[body.Inputs]
+	if !ok {
+		labels = s.fallback
+	}
+	entities := s.entities[body.Inputs]
+	delay := s.delay
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case status != 0:
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":"fake failure"}`))
+	case loading:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"Model is currently loading","estimated_time":0.01}`))
+	case body.Parameters != nil:
+		if entities == nil {
+			entities = []Entity{}
+		}
+		_ = json.NewEncoder(w).Encode(entities)
+	default:
+		_ = json.NewEncoder(w).Encode([][]Label{labels})
+	}
+}
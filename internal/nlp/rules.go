@@ -0,0 +1,60 @@
+package nlp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// RuleClassifier implements IntentClassifier with Portuguese keyword rules.
+// Works offline; used when the remote model is unavailable or unsure.
+type RuleClassifier struct {
+	rules []intentRule
+}
+
+// intentRule maps pattern (on lowercased, accent-free text) to intent.
+type intentRule struct {
+	intent     Intent
+	re         *regexp.Regexp
+	confidence float64
+}
+
+// NewRuleClassifier creates rule-based classifier.
+// Rules are evaluated in order; first match wins, so more specific
+// intents (reschedule, cancel) come before generic ones (schedule).
+func NewRuleClassifier() *RuleClassifier {
+	return &RuleClassifier{rules: []intentRule{
+		{IntentReschedule, regexp.MustCompile(`\b(remarcar|remarca|reagendar|reagenda|adiar|antecipar|(mudar|trocar|alterar)\s+(o\s+|a\s+|meu\s+|minha\s+)?(horario|dia|data|consulta))\b`), 0.9},
+		{IntentCancel, regexp.MustCompile(`\b(cancelar|cancela|cancelo|desmarcar|desmarca|nao\s+vou(\s+poder)?(\s+(ir|comparecer))?|nao\s+(posso|poderei|consigo)\s+(ir|comparecer))\b`), 0.9},
+		{IntentConfirm, regexp.MustCompile(`\b(confirmo|confirmado|confirmada|confirmar|confirma|estarei\s+la|vou\s+sim|vou\s+comparecer)\b`), 0.9},
+		{IntentConfirm, regexp.MustCompile(`^\s*(sim|s|ok|certo|combinado|beleza|blz|pode\s+ser|claro|1)\s*[.!]*\s*$`), 0.8},
+		{IntentCancel, regexp.MustCompile(`^\s*(nao|n|2)\s*[.!]*\s*$`), 0.6},
+		{IntentListAppointments, regexp.MustCompile(`\b(minhas\s+consultas|meus\s+(agendamentos|horarios)|minha\s+consulta|quando\s+e\s+(a\s+)?minha|tenho\s+(alguma\s+)?consulta|qual\s+(e\s+)?(o\s+)?meu\s+horario|ver\s+(meus|minhas))\b`), 0.85},
+		{IntentSchedule, regexp.MustCompile(`\b(marcar|marca|agendar|agenda|agendamento|nova\s+consulta|quero\s+uma\s+consulta|tem\s+(horario|vaga)|horario\s+disponivel|atendimento)\b`), 0.85},
+		{IntentSchedule, regexp.MustCompile(`\b(consulta|horario|vaga)\b`), 0.6},
+		{IntentGreeting, regexp.MustCompile(`^\s*(oi+|ola|opa|bom\s+dia|boa\s+tarde|boa\s+noite|e\s+ai|tudo\s+bem|hello|hi)\b`), 0.8},
+	}}
+}
+
+// Classify matches rules against normalized text.
+// Returns IntentUnknown with zero confidence when nothing matches.
+func (c *RuleClassifier) Classify(ctx context.Context, text string) (*Classification, error) {
+	normalized := Normalize(text)
+
+	for _, rule := range c.rules {
+		if m := rule.re.FindString(normalized); m != "" {
+			return &Classification{
+				Intent:     rule.intent,
+				Confidence: rule.confidence,
+				Label:      "rule:" + strings.TrimSpace(m),
+			}, nil
+		}
+	}
+
+	return &Classification{Intent: IntentUnknown, Confidence: 0, Label: "rule:none"}, nil
+}
+
+// Normalize lowercases text, strips Portuguese diacritics and surrounding space.
+func Normalize(text string) string {
+	return accentReplacer.Replace(strings.ToLower(strings.TrimSpace(text)))
+}
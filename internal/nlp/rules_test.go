@@ -0,0 +1,101 @@
+package nlp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRuleClassifier(t *testing.T) {
+	c := NewRuleClassifier()
+
+	tests := []struct {
+		text       string
+		intent     Intent
+		confidence float64
+	}{
+		// Reschedule before cancel and schedule
+		{"Preciso remarcar minha consulta", IntentReschedule, 0.9},
+		{"dá pra trocar o horário?", IntentReschedule, 0.9},
+		{"quero reagendar", IntentReschedule, 0.9},
+		{"posso antecipar para amanhã?", IntentReschedule, 0.9},
+
+		{"quero CANCELAR a consulta", IntentCancel, 0.9},
+		{"Não vou poder ir amanhã", IntentCancel, 0.9},
+		{"nao consigo comparecer", IntentCancel, 0.9},
+		{"pode desmarcar", IntentCancel, 0.9},
+
+		{"Confirmo!", IntentConfirm, 0.9},
+		{"estarei lá", IntentConfirm, 0.9},
+		{"vou sim", IntentConfirm, 0.9},
+		{"Sim", IntentConfirm, 0.8},
+		{" ok. ", IntentConfirm, 0.8},
+		{"pode ser", IntentConfirm, 0.8},
+		{"1", IntentConfirm, 0.8},
+
+		// Terse negatives only count on their own, with low confidence
+		{"Não", IntentCancel, 0.6},
+		{"n", IntentCancel, 0.6},
+		{"2", IntentCancel, 0.6},
+		{"2 pessoas", IntentUnknown, 0},
+
+		{"quais são minhas consultas?", IntentListAppointments, 0.85},
+		{"quando é a minha?", IntentListAppointments, 0.85},
+		{"tenho alguma consulta marcada", IntentListAppointments, 0.85},
+
+		{"Oi, quero agendar uma consulta", IntentSchedule, 0.85},
+		{"tem horário disponível na sexta?", IntentSchedule, 0.85},
+		{"gostaria de marcar", IntentSchedule, 0.85},
+		{"e a consulta?", IntentSchedule, 0.6},
+
+		{"Olá", IntentGreeting, 0.8},
+		{"bom dia!", IntentGreeting, 0.8},
+		{"oiii tudo bem", IntentGreeting, 0.8},
+
+		{"qual o endereço da clínica?", IntentUnknown, 0},
+		{"", IntentUnknown, 0},
+		// Words are matched whole
+		{"marcação de ponto desmarcado", IntentUnknown, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := c.Classify(context.Background(), tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Intent != tt.intent || got.Confidence != tt.confidence {
+				t.Errorf("Classify(%q) = %s %.2f (%s), want %s %.2f",
+					tt.text, got.Intent, got.Confidence, got.Label, tt.intent, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestRuleClassifierLabel(t *testing.T) {
+	got, err := NewRuleClassifier().Classify(context.Background(), "  Quero DESMARCAR  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Label != "rule:desmarcar" {
+		t.Errorf("Label = %q, want %q", got.Label, "rule:desmarcar")
+	}
+
+	got, _ = NewRuleClassifier().Classify(context.Background(), "qual o endereço?")
+	if got.Label != "rule:none" {
+		t.Errorf("Label without match = %q, want %q", got.Label, "rule:none")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"  Não  ":              "nao",
+		"AMANHÃ às 15h":        "amanha as 15h",
+		"Terça-feira, horário": "terca-feira, horario",
+		"você está lá?":        "voce esta la?",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package reminder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+)
+
+// weekdayNames holds Portuguese weekday names.
+var weekdayNames = [...]string{"domingo", "segunda", "terça", "quarta", "quinta", "sexta", "sábado"}
+
+// Message renders reminder text for appointment, relative to now.
+func Message(patient *domain.Patient, apt *domain.Appointment, now time.Time, loc *time.Location) string {
+	start, end := apt.DateTime.In(loc), apt.End().In(loc)
+	name, _, _ := strings.Cut(strings.TrimSpace(patient.Name), " ")
+
+	return fmt.Sprintf("Olá, %s! Lembrete: sua consulta é %s, das %s às %s.\n"+
+		"Responda SIM para confirmar ou NÃO para cancelar.",
+		name, relativeDay(start, now), start.Format("15:04"), end.Format("15:04"))
+}
+
+// relativeDay describes day as "hoje", "amanhã" or "terça, 20/10".
+func relativeDay(t, now time.Time) string {
+	ty, tm, td := t.Date()
+	ny, nm, nd := now.Date()
+	day := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+	today := time.Date(ny, nm, nd, 0, 0, 0, 0, time.UTC)
+
+	switch day.Sub(today) {
+	case 0:
+		return "hoje"
+	case 24 * time.Hour:
+		return "amanhã"
+	}
+	return fmt.Sprintf("%s, %s", weekdayNames[t.Weekday()], t.Format("02/01"))
+}
@@ -0,0 +1,210 @@
+// Package reminder sends WhatsApp reminders ahead of appointments.
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// Sender delivers text messages.
+// Implemented by whatsapp.Client.
+type Sender interface {
+	// SendTo sends text to phone number and returns message ID.
+	SendTo(to, text string) (string, error)
+}
+
+// Scheduler periodically scans upcoming appointments and sends reminders.
+type Scheduler struct {
+	appointments repository.AppointmentRepository
+	patients     repository.PatientRepository
+	reminders    repository.ReminderRepository
+	sender       Sender
+	offsets      []time.Duration // Descending
+	interval     time.Duration
+	loc          *time.Location
+	now          func() time.Time
+}
+
+// NewScheduler creates reminder scheduler.
+// Offsets are how long before an appointment reminders go out (e.g. 24h, 2h).
+func NewScheduler(
+	appointments repository.AppointmentRepository,
+	patients repository.PatientRepository,
+	reminders repository.ReminderRepository,
+	sender Sender,
+	offsets []time.Duration,
+	interval time.Duration,
+	loc *time.Location,
+) *Scheduler {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	return &Scheduler{
+		appointments: appointments,
+		patients:     patients,
+		reminders:    reminders,
+		sender:       sender,
+		offsets:      sorted,
+		interval:     interval,
+		loc:          loc,
+		now:          time.Now,
+	}
+}
+
+// ParseOffsets parses comma-separated durations like "24h,2h".
+func ParseOffsets(s string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(item)
+		if err != nil {
+			return nil, fmt.Errorf("offset %q: %w", item, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("offset %q: must be positive", item)
+		}
+		offsets = append(offsets, d)
+	}
+	return offsets, nil
+}
+
+// Run scans every interval until ctx is cancelled.
+// Returns immediately when no offsets are configured.
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.offsets) == 0 || s.interval <= 0 {
+		log.Info().Msg("reminders disabled")
+		return
+	}
+
+	log.Info().
+		Str("offsets", fmt.Sprint(s.offsets)).
+		Dur("interval", s.interval).
+		Msg("reminder scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("reminder scan failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends reminders due now.
+// Each appointment gets at most the tightest offset it is within, so an
+// appointment booked 1h ahead gets only the 2h reminder, not the 24h one.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	if len(s.offsets) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	list := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return s.appointments.ListByDateRange(ctx, now, now.Add(s.offsets[0]), opts)
+	}
+
+	var errs []error
+	for apt, err := range repository.StreamAppointments(ctx, repository.ListOptions{}, list) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list upcoming appointments: %w", err))
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !apt.IsOpen() || !apt.DateTime.After(now) {
+			continue
+		}
+
+		offset, ok := s.dueOffset(apt.DateTime.Sub(now))
+		if !ok {
+			continue
+		}
+
+		if err := s.send(ctx, apt, offset, now); err != nil {
+			errs = append(errs, fmt.Errorf("appointment %s: %w", apt.ID.Hex(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dueOffset returns the smallest offset that until falls within.
+func (s *Scheduler) dueOffset(until time.Duration) (time.Duration, bool) {
+	for i := len(s.offsets) - 1; i >= 0; i-- {
+		if until <= s.offsets[i] {
+			return s.offsets[i], true
+		}
+	}
+	return 0, false
+}
+
+// send records reminder, then delivers it.
+// Recording first means a crash mid-send skips a reminder rather than repeating it.
+func (s *Scheduler) send(ctx context.Context, apt *domain.Appointment, offset time.Duration, now time.Time) error {
+	rem := &domain.Reminder{
+		Appointment:   apt.ID,
+		Patient:       apt.Patient,
+		AppointmentAt: apt.DateTime,
+		Offset:        offset,
+	}
+	err := s.reminders.Create(ctx, rem)
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return nil // Already sent
+	case err != nil:
+		return fmt.Errorf("failed to record reminder: %w", err)
+	}
+
+	patient, err := s.patients.GetByID(ctx, apt.Patient)
+	if err != nil {
+		s.release(ctx, rem)
+		return fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	messageID, err := s.sender.SendTo(patient.Phone, Message(patient, apt, now.In(s.loc), s.loc))
+	if err != nil {
+		// Release so the next scan retries
+		s.release(ctx, rem)
+		return fmt.Errorf("failed to send reminder: %w", err)
+	}
+
+	if err := s.reminders.MarkSent(ctx, rem.ID, messageID, s.now()); err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	}
+
+	log.Info().
+		Str("appointment_id", apt.ID.Hex()).
+		Str("patient_id", patient.ID.Hex()).
+		Dur("offset", offset).
+		Str("message_id", messageID).
+		Msg("reminder sent")
+	return nil
+}
+
+// release deletes reminder record after a failed send.
+func (s *Scheduler) release(ctx context.Context, rem *domain.Reminder) {
+	if err := s.reminders.Delete(context.WithoutCancel(ctx), rem.ID); err != nil {
+		log.Error().Err(err).Str("reminder_id", rem.ID.Hex()).Msg("failed to release reminder")
+	}
+}
@@ -0,0 +1,290 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/memory"
+)
+
+// fakeSender records messages; the next fail sends return an error.
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []string // "<to>: <text>"
+	fail int
+}
+
+func (f *fakeSender) SendTo(to, text string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail > 0 {
+		f.fail--
+		return "", errors.New("whatsapp unavailable")
+	}
+	f.sent = append(f.sent, to+": "+text)
+	return fmt.Sprintf("MSG-%d", len(f.sent)), nil
+}
+
+func (f *fakeSender) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.sent)
+}
+
+// schedulerTest holds repositories shared by schedulers, as after a restart.
+type schedulerTest struct {
+	t            *testing.T
+	appointments repository.AppointmentRepository
+	patients     repository.PatientRepository
+	reminders    repository.ReminderRepository
+	sender       *fakeSender
+	patient      *domain.Patient
+	now          time.Time
+}
+
+func newSchedulerTest(t *testing.T) *schedulerTest {
+	t.Helper()
+	appointments := memory.NewAppointmentRepository()
+	st := &schedulerTest{
+		t:            t,
+		appointments: appointments,
+		patients:     memory.NewPatientRepository(appointments),
+		reminders:    memory.NewReminderRepository(),
+		sender:       &fakeSender{},
+		patient:      &domain.Patient{Name: "Maria da Silva", Phone: "+5511988887777"},
+		now:          time.Date(2030, 1, 7, 8, 0, 0, 0, time.UTC),
+	}
+	if err := st.patients.Create(context.Background(), st.patient); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+// scheduler returns a scheduler with 24h and 2h offsets, as of st.now.
+func (st *schedulerTest) scheduler() *Scheduler {
+	s := NewScheduler(st.appointments, st.patients, st.reminders, st.sender,
+		[]time.Duration{2 * time.Hour, 24 * time.Hour}, time.Minute, time.UTC)
+	s.now = func() time.Time { return st.now }
+	return s
+}
+
+// book stores a pending appointment starting in from now.
+func (st *schedulerTest) book(in time.Duration) *domain.Appointment {
+	st.t.Helper()
+	start := st.now.Add(in)
+	apt := &domain.Appointment{DateTime: start, EndTime: start.Add(50 * time.Minute), Patient: st.patient.ID}
+	if err := apt.Transition(domain.StatusPending, domain.ActorPatient, "", st.now); err != nil {
+		st.t.Fatal(err)
+	}
+	if err := st.appointments.Create(context.Background(), apt); err != nil {
+		st.t.Fatal(err)
+	}
+	return apt
+}
+
+// offsets returns offsets of reminders recorded for apt.
+func (st *schedulerTest) offsets(apt *domain.Appointment) []time.Duration {
+	st.t.Helper()
+	list, err := st.reminders.ListByAppointment(context.Background(), apt.ID)
+	if err != nil {
+		st.t.Fatal(err)
+	}
+	offsets := make([]time.Duration, len(list))
+	for i, r := range list {
+		offsets[i] = r.Offset
+	}
+	return offsets
+}
+
+func (st *schedulerTest) tick(s *Scheduler) {
+	st.t.Helper()
+	if err := s.Tick(context.Background()); err != nil {
+		st.t.Fatalf("Tick: %v", err)
+	}
+}
+
+func TestTickSendsOnceAcrossRestarts(t *testing.T) {
+	st := newSchedulerTest(t)
+	apt := st.book(20 * time.Hour)
+
+	st.tick(st.scheduler())
+	st.tick(st.scheduler()) // Restarted process
+	if n := st.sender.count(); n != 1 {
+		t.Fatalf("sent %d reminders, want 1", n)
+	}
+	if !strings.HasPrefix(st.sender.sent[0], "+5511988887777: Olá, Maria! Lembrete: sua consulta é amanhã, das 04:00 às 04:50.") {
+		t.Errorf("sent %q", st.sender.sent[0])
+	}
+
+	list, err := st.reminders.ListByAppointment(context.Background(), apt.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].MessageID != "MSG-1" || !list[0].SentAt.Equal(st.now) {
+		t.Errorf("reminders: got %d, want one sent as MSG-1 at %s", len(list), st.now)
+	}
+
+	// A claim left by a process that crashed mid-send also blocks sending
+	other := st.book(30 * time.Hour)
+	claim := &domain.Reminder{Appointment: other.ID, Patient: st.patient.ID, AppointmentAt: other.DateTime, Offset: 24 * time.Hour}
+	if err := st.reminders.Create(context.Background(), claim); err != nil {
+		t.Fatal(err)
+	}
+	st.now = st.now.Add(7 * time.Hour)
+	st.tick(st.scheduler())
+	if n := st.sender.count(); n != 1 {
+		t.Errorf("sent %d reminders with a claim held, want 1", n)
+	}
+}
+
+func TestTickUsesTightestDueOffset(t *testing.T) {
+	st := newSchedulerTest(t)
+	soon := st.book(time.Hour)
+	later := st.book(10 * time.Hour)
+	farAway := st.book(30 * time.Hour)
+
+	s := st.scheduler()
+	st.tick(s)
+	for _, tt := range []struct {
+		name string
+		apt  *domain.Appointment
+		want []time.Duration
+	}{
+		{"within 2h", soon, []time.Duration{2 * time.Hour}},
+		{"within 24h", later, []time.Duration{24 * time.Hour}},
+		{"beyond 24h", farAway, []time.Duration{}},
+	} {
+		if got := st.offsets(tt.apt); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got offsets %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Closer to start, the next offset is due as well
+	st.now = st.now.Add(9 * time.Hour)
+	st.tick(s)
+	if got := st.offsets(later); fmt.Sprint(got) != fmt.Sprint([]time.Duration{24 * time.Hour, 2 * time.Hour}) {
+		t.Errorf("later after 9h: got offsets %v, want [24h 2h]", got)
+	}
+	if n := st.sender.count(); n != 4 {
+		t.Errorf("sent %d reminders, want 4", n)
+	}
+}
+
+func TestDueOffset(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, nil, []time.Duration{2 * time.Hour, 24 * time.Hour, 30 * time.Minute}, time.Minute, time.UTC)
+	tests := []struct {
+		until time.Duration
+		want  time.Duration
+		ok    bool
+	}{
+		{10 * time.Minute, 30 * time.Minute, true},
+		{30 * time.Minute, 30 * time.Minute, true},
+		{31 * time.Minute, 2 * time.Hour, true},
+		{2 * time.Hour, 2 * time.Hour, true},
+		{23 * time.Hour, 24 * time.Hour, true},
+		{25 * time.Hour, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := s.dueOffset(tt.until)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("dueOffset(%s) = %s, %v, want %s, %v", tt.until, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTickRetriesFailedSend(t *testing.T) {
+	st := newSchedulerTest(t)
+	apt := st.book(20 * time.Hour)
+	s := st.scheduler()
+
+	st.sender.fail = 1
+	if err := s.Tick(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to send reminder") {
+		t.Fatalf("Tick with failing sender: got %v, want send error", err)
+	}
+	if got := st.offsets(apt); len(got) != 0 {
+		t.Fatalf("claim kept after failed send: %v", got)
+	}
+
+	st.tick(s)
+	if n := st.sender.count(); n != 1 {
+		t.Errorf("sent %d reminders on retry, want 1", n)
+	}
+	if got := st.offsets(apt); len(got) != 1 {
+		t.Errorf("reminders after retry: got %v, want one", got)
+	}
+}
+
+func TestTickSkipsClosedAppointments(t *testing.T) {
+	st := newSchedulerTest(t)
+	apt := st.book(20 * time.Hour)
+	if err := apt.Transition(domain.StatusCancelled, domain.ActorPatient, "", st.now); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.appointments.Update(context.Background(), apt); err != nil {
+		t.Fatal(err)
+	}
+
+	st.tick(st.scheduler())
+	if n := st.sender.count(); n != 0 {
+		t.Errorf("sent %d reminders for a cancelled appointment", n)
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	st := newSchedulerTest(t)
+	st.book(20 * time.Hour)
+	s := st.scheduler()
+	s.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for st.sender.count() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("no reminder sent by Run")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	if n := st.sender.count(); n != 1 {
+		t.Errorf("sent %d reminders, want 1", n)
+	}
+}
+
+func TestRunDisabled(t *testing.T) {
+	st := newSchedulerTest(t)
+	for _, s := range []*Scheduler{
+		NewScheduler(st.appointments, st.patients, st.reminders, st.sender, nil, time.Minute, time.UTC),
+		NewScheduler(st.appointments, st.patients, st.reminders, st.sender, []time.Duration{time.Hour}, 0, time.UTC),
+	} {
+		done := make(chan struct{})
+		go func() {
+			s.Run(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("disabled Run did not return")
+		}
+	}
+}
@@ -2,20 +2,70 @@ package repository
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/matheusmassa1/clara/internal/domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// AppointmentRepository defines appointment data access operations
+// AppointmentRepository defines appointment data access operations.
+// List methods return one page per call; see ListOptions and StreamAppointments.
+// ListByX methods are shorthands for Find with a single filter field.
+// Reads skip soft-deleted appointments unless ctx comes from WithDeleted.
 type AppointmentRepository interface {
 	Create(ctx context.Context, apt *domain.Appointment) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Appointment, error)
-	List(ctx context.Context) ([]*domain.Appointment, error)
+	List(ctx context.Context, opts ListOptions) (*AppointmentPage, error)
+	Find(ctx context.Context, filter AppointmentFilter, opts ListOptions) (*AppointmentPage, error)
 	Update(ctx context.Context, apt *domain.Appointment) error
+	// Reschedule creates next and updates orig (marked rescheduled, linked to
+	// next) as one change: if either fails, neither is kept. Backends without
+	// transactions undo next instead; if that undo fails too, its error is
+	// joined to the returned one and next may remain. next may carry a
+	// preassigned ID for orig to link to.
+	Reschedule(ctx context.Context, orig, next *domain.Appointment) error
+	// Delete soft-deletes appointment by the actor of ctx (see WithActor), freeing its slot.
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	ListByPatient(ctx context.Context, patientID primitive.ObjectID) ([]*domain.Appointment, error)
-	ListByDateRange(ctx context.Context, start, end time.Time) ([]*domain.Appointment, error)
-	ListByStatus(ctx context.Context, status string) ([]*domain.Appointment, error)
+	// Restore undeletes appointment. Returns ErrConflict if its slot was taken meanwhile.
+	Restore(ctx context.Context, id primitive.ObjectID) error
+	// Purge removes appointments deleted before cutoff with their reminders.
+	// Returns number of appointments removed.
+	Purge(ctx context.Context, cutoff time.Time) (int, error)
+	ListByPatient(ctx context.Context, patientID primitive.ObjectID, opts ListOptions) (*AppointmentPage, error)
+	// ListByDateRange lists appointments overlapping [start, end).
+	ListByDateRange(ctx context.Context, start, end time.Time, opts ListOptions) (*AppointmentPage, error)
+	ListByStatus(ctx context.Context, status string, opts ListOptions) (*AppointmentPage, error)
+}
+
+// AppointmentFilter selects appointments for Find.
+// Zero fields match any appointment; set fields must all match.
+type AppointmentFilter struct {
+	Patient      primitive.ObjectID
+	Statuses     []string // Any of these statuses
+	Professional string
+	Start, End   time.Time // Overlapping [Start, End); zero leaves that side open
+
+	CreatedFrom, CreatedTo time.Time // CreatedAt within [CreatedFrom, CreatedTo)
+}
+
+// Match reports whether apt satisfies f.
+func (f AppointmentFilter) Match(apt *domain.Appointment) bool {
+	switch {
+	case !f.Patient.IsZero() && apt.Patient != f.Patient:
+		return false
+	case len(f.Statuses) > 0 && !slices.Contains(f.Statuses, apt.Status):
+		return false
+	case f.Professional != "" && apt.Professional != f.Professional:
+		return false
+	case !f.Start.IsZero() && !apt.End().After(f.Start):
+		return false
+	case !f.End.IsZero() && !apt.DateTime.Before(f.End):
+		return false
+	case !f.CreatedFrom.IsZero() && apt.CreatedAt.Before(f.CreatedFrom):
+		return false
+	case !f.CreatedTo.IsZero() && !apt.CreatedAt.Before(f.CreatedTo):
+		return false
+	}
+	return true
 }
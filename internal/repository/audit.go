@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditRepository stores the audit log. It is append-only: there is no way
+// to change or remove an entry once appended.
+type AuditRepository interface {
+	Append(ctx context.Context, entry *domain.AuditEntry) error
+	// ListByEntity returns entries of one patient or appointment, oldest first.
+	ListByEntity(ctx context.Context, entity string, id primitive.ObjectID) ([]*domain.AuditEntry, error)
+}
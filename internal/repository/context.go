@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+)
+
+// contextKey keys repository values in a context
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	correlationIDKey
+	includeDeletedKey
+)
+
+// WithActor returns ctx attributing writes (e.g. DeletedBy) to actor,
+// one of the domain Actor constants.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns actor set by WithActor, domain.ActorSystem if none.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return domain.ActorSystem
+}
+
+// WithCorrelationID returns ctx tying writes to what caused them, e.g. the ID
+// of an inbound WhatsApp message.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationIDFrom returns ID set by WithCorrelationID, empty if none.
+func CorrelationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// WithDeleted returns ctx whose reads also return soft-deleted records.
+// Writes never apply to soft-deleted records.
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey, true)
+}
+
+// IncludesDeleted reports whether reads with ctx return soft-deleted records.
+func IncludesDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey).(bool)
+	return include
+}
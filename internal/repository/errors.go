@@ -11,4 +11,10 @@ var (
 
 	// ErrInvalidInput is returned when input validation fails
 	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrConflict is returned when entity clashes with existing one (e.g. overlapping appointment)
+	ErrConflict = errors.New("entity conflicts with existing one")
+
+	// ErrHasDependents is returned when entity cannot be removed while others depend on it
+	ErrHasDependents = errors.New("entity has dependents")
 )
@@ -0,0 +1,303 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AppointmentRepo implements repository.AppointmentRepository in memory.
+// Mirrors the MongoDB repository: overlapping non-cancelled appointments are
+// rejected with repository.ErrConflict, status changes must be allowed
+// transitions, deletes are soft, and times are stored with millisecond
+// precision in UTC.
+type AppointmentRepo struct {
+	mu           sync.RWMutex
+	appointments map[primitive.ObjectID]domain.Appointment
+}
+
+// NewAppointmentRepository creates a new in-memory appointment repository
+func NewAppointmentRepository() repository.AppointmentRepository {
+	return &AppointmentRepo{appointments: make(map[primitive.ObjectID]domain.Appointment)}
+}
+
+// Create inserts a new appointment
+func (r *AppointmentRepo) Create(ctx context.Context, apt *domain.Appointment) error {
+	if err := apt.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.create(apt)
+}
+
+// create inserts validated apt. Caller holds lock.
+func (r *AppointmentRepo) create(apt *domain.Appointment) error {
+	id := apt.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+	if _, exists := r.appointments[id]; exists {
+		return repository.ErrDuplicate
+	}
+
+	stored := storedAppointment(apt)
+	stored.ID = id
+	stored.DeletedAt, stored.DeletedBy = time.Time{}, ""
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = storedTime(time.Now())
+	}
+	if stored.BlocksAgenda() && r.overlaps(&stored) {
+		return repository.ErrConflict
+	}
+
+	apt.ID = id
+	apt.CreatedAt = stored.CreatedAt
+	r.appointments[id] = stored
+	return nil
+}
+
+// GetByID retrieves appointment by ID
+func (r *AppointmentRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Appointment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	apt, ok := r.appointments[id]
+	if !ok || apt.IsDeleted() && !repository.IncludesDeleted(ctx) {
+		return nil, repository.ErrNotFound
+	}
+	return cloneAppointment(&apt), nil
+}
+
+// List retrieves a page of all appointments
+func (r *AppointmentRepo) List(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{}, opts)
+}
+
+// Find retrieves a page of appointments matching filter
+func (r *AppointmentRepo) Find(ctx context.Context, filter repository.AppointmentFilter, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	includeDeleted := repository.IncludesDeleted(ctx)
+	return r.page(func(apt *domain.Appointment) bool {
+		return (includeDeleted || !apt.IsDeleted()) && filter.Match(apt)
+	}, opts)
+}
+
+// Update updates existing appointment.
+// Status changes must be allowed transitions (returns *domain.TransitionError).
+func (r *AppointmentRepo) Update(ctx context.Context, apt *domain.Appointment) error {
+	if err := apt.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.update(apt)
+}
+
+// update replaces stored apt with validated apt. Caller holds lock.
+func (r *AppointmentRepo) update(apt *domain.Appointment) error {
+	current, ok := r.appointments[apt.ID]
+	if !ok || current.IsDeleted() {
+		return repository.ErrNotFound
+	}
+	if current.Status != apt.Status {
+		if err := domain.CheckTransition(current.Status, apt.Status); err != nil {
+			return err
+		}
+	}
+
+	stored := storedAppointment(apt)
+	if stored.BlocksAgenda() && r.overlaps(&stored) {
+		return repository.ErrConflict
+	}
+
+	// Creation time is kept; lineage links are only ever added
+	stored.CreatedAt = current.CreatedAt
+	stored.DeletedAt, stored.DeletedBy = time.Time{}, ""
+	if stored.RescheduledFrom.IsZero() {
+		stored.RescheduledFrom = current.RescheduledFrom
+	}
+	if stored.RescheduledTo.IsZero() {
+		stored.RescheduledTo = current.RescheduledTo
+	}
+
+	r.appointments[apt.ID] = stored
+	return nil
+}
+
+// Reschedule creates next and updates orig under one lock
+func (r *AppointmentRepo) Reschedule(ctx context.Context, orig, next *domain.Appointment) error {
+	if orig.Validate() != nil || next.Validate() != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, createdAt := next.ID, next.CreatedAt
+	if err := r.create(next); err != nil {
+		return err
+	}
+	if err := r.update(orig); err != nil {
+		delete(r.appointments, next.ID)
+		next.ID, next.CreatedAt = id, createdAt
+		return err
+	}
+	return nil
+}
+
+// Delete soft-deletes appointment by ID
+func (r *AppointmentRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	apt, ok := r.appointments[id]
+	if !ok || apt.IsDeleted() {
+		return repository.ErrNotFound
+	}
+
+	apt.DeletedAt = storedTime(time.Now())
+	apt.DeletedBy = repository.ActorFrom(ctx)
+	r.appointments[id] = apt
+	return nil
+}
+
+// Restore undeletes appointment by ID
+func (r *AppointmentRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	apt, ok := r.appointments[id]
+	if !ok || !apt.IsDeleted() {
+		return repository.ErrNotFound
+	}
+	if apt.BlocksAgenda() && r.overlaps(&apt) {
+		return repository.ErrConflict
+	}
+
+	apt.DeletedAt, apt.DeletedBy = time.Time{}, ""
+	r.appointments[id] = apt
+	return nil
+}
+
+// Purge removes appointments deleted before cutoff
+func (r *AppointmentRepo) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int
+	for id, apt := range r.appointments {
+		if apt.IsDeleted() && apt.DeletedAt.Before(cutoff) {
+			delete(r.appointments, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgePatient removes all appointments of patient, deleted or not
+func (r *AppointmentRepo) purgePatient(patientID primitive.ObjectID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, apt := range r.appointments {
+		if apt.Patient == patientID {
+			delete(r.appointments, id)
+		}
+	}
+}
+
+// ListByPatient retrieves a page of appointments for patient
+func (r *AppointmentRepo) ListByPatient(ctx context.Context, patientID primitive.ObjectID, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Patient: patientID}, opts)
+}
+
+// ListByDateRange retrieves a page of appointments overlapping [start, end)
+func (r *AppointmentRepo) ListByDateRange(ctx context.Context, start, end time.Time, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Start: start, End: end}, opts)
+}
+
+// ListByStatus retrieves a page of appointments by status
+func (r *AppointmentRepo) ListByStatus(ctx context.Context, status string, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Statuses: []string{status}}, opts)
+}
+
+// page returns copies of one page of matching appointments
+func (r *AppointmentRepo) page(match func(*domain.Appointment) bool, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	pos, err := opts.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*domain.Appointment
+	for _, apt := range r.appointments {
+		if !match(&apt) {
+			continue
+		}
+		if pos != nil && opts.Compare(repository.PositionOf(&apt), *pos) <= 0 {
+			continue
+		}
+		matched = append(matched, cloneAppointment(&apt))
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return opts.Compare(repository.PositionOf(matched[i]), repository.PositionOf(matched[j])) < 0
+	})
+
+	if len(matched) > opts.Limit+1 {
+		matched = matched[:opts.Limit+1]
+	}
+	return opts.NewPage(matched), nil
+}
+
+// overlaps reports whether another blocking appointment intersects apt. Caller holds lock.
+func (r *AppointmentRepo) overlaps(apt *domain.Appointment) bool {
+	for id, other := range r.appointments {
+		if id == apt.ID || other.IsDeleted() || !other.BlocksAgenda() {
+			continue
+		}
+		if apt.DateTime.Before(other.End()) && other.DateTime.Before(apt.End()) {
+			return true
+		}
+	}
+	return false
+}
+
+// storedAppointment copies apt with times truncated to milliseconds in UTC,
+// as a MongoDB round trip would return them.
+func storedAppointment(apt *domain.Appointment) domain.Appointment {
+	c := *cloneAppointment(apt)
+	c.DateTime = storedTime(c.DateTime)
+	c.EndTime = storedTime(c.EndTime)
+	c.CreatedAt = storedTime(c.CreatedAt)
+	c.DeletedAt = storedTime(c.DeletedAt)
+	for i := range c.StatusHistory {
+		c.StatusHistory[i].At = storedTime(c.StatusHistory[i].At)
+	}
+	return c
+}
+
+// cloneAppointment copies appointment so callers never share its history
+func cloneAppointment(apt *domain.Appointment) *domain.Appointment {
+	c := *apt
+	if apt.StatusHistory != nil {
+		c.StatusHistory = append([]domain.StatusChange(nil), apt.StatusHistory...)
+	}
+	return &c
+}
+
+// storedTime truncates t to BSON datetime precision
+func storedTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Truncate(time.Millisecond).UTC()
+}
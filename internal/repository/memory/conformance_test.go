@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/repository/repotest"
+)
+
+func TestPatientConformance(t *testing.T) {
+	appointments := NewAppointmentRepository()
+	if err := repotest.Patients(context.Background(), NewPatientRepository(appointments), appointments); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppointmentConformance(t *testing.T) {
+	if err := repotest.Appointments(context.Background(), NewAppointmentRepository()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReminderConformance(t *testing.T) {
+	if err := repotest.Reminders(context.Background(), NewReminderRepository()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionConformance(t *testing.T) {
+	ttl := 300 * time.Millisecond
+	if err := repotest.Sessions(context.Background(), NewSessionRepository(ttl), ttl); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,287 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PatientRepo implements repository.PatientRepository in memory.
+// Phone is unique among live and deleted patients, like the MongoDB phone index.
+type PatientRepo struct {
+	mu           sync.RWMutex
+	patients     map[primitive.ObjectID]domain.Patient
+	order        []primitive.ObjectID // Insertion order
+	appointments repository.AppointmentRepository
+}
+
+// NewPatientRepository creates a new in-memory patient repository.
+// Delete, Restore and Purge also apply to patient appointments in appointments, if not nil.
+func NewPatientRepository(appointments repository.AppointmentRepository) repository.PatientRepository {
+	return &PatientRepo{
+		patients:     make(map[primitive.ObjectID]domain.Patient),
+		appointments: appointments,
+	}
+}
+
+// Create inserts a new patient
+func (r *PatientRepo) Create(ctx context.Context, patient *domain.Patient) error {
+	if err := patient.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := patient.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+	if _, exists := r.patients[id]; exists || r.phoneTaken(patient.Phone, id) {
+		return repository.ErrDuplicate
+	}
+
+	patient.ID = id
+	patient.DeletedAt, patient.DeletedBy = time.Time{}, ""
+	r.patients[id] = *patient
+	r.order = append(r.order, id)
+	return nil
+}
+
+// GetByID retrieves patient by ID
+func (r *PatientRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Patient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patient, ok := r.patients[id]
+	if !ok || patient.IsDeleted() && !repository.IncludesDeleted(ctx) {
+		return nil, repository.ErrNotFound
+	}
+	return &patient, nil
+}
+
+// GetByPhone retrieves patient by phone
+func (r *PatientRepo) GetByPhone(ctx context.Context, phone string) (*domain.Patient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.order {
+		patient := r.patients[id]
+		if patient.Phone == phone && (!patient.IsDeleted() || repository.IncludesDeleted(ctx)) {
+			return &patient, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Update updates existing patient
+func (r *PatientRepo) Update(ctx context.Context, patient *domain.Patient) error {
+	if err := patient.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.patients[patient.ID]; !ok || current.IsDeleted() {
+		return repository.ErrNotFound
+	}
+	if r.phoneTaken(patient.Phone, patient.ID) {
+		return repository.ErrDuplicate
+	}
+
+	stored := *patient
+	stored.DeletedAt, stored.DeletedBy = time.Time{}, ""
+	r.patients[patient.ID] = stored
+	return nil
+}
+
+// List retrieves patients ordered by name
+func (r *PatientRepo) List(ctx context.Context, page repository.Page) ([]*domain.Patient, error) {
+	return r.filter(ctx, func(*domain.Patient) bool { return true }, page), nil
+}
+
+// Search retrieves patients whose name words start with every query term
+func (r *PatientRepo) Search(ctx context.Context, query string, page repository.Page) ([]*domain.Patient, error) {
+	terms := strings.Fields(domain.NormalizeName(query))
+	return r.filter(ctx, func(p *domain.Patient) bool {
+		name := " " + domain.NormalizeName(p.Name)
+		for _, term := range terms {
+			if !strings.Contains(name, " "+term) {
+				return false
+			}
+		}
+		return true
+	}, page), nil
+}
+
+// Delete soft-deletes patient and their appointments
+func (r *PatientRepo) Delete(ctx context.Context, id primitive.ObjectID, cascade bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	patient, ok := r.patients[id]
+	if !ok || patient.IsDeleted() {
+		return repository.ErrNotFound
+	}
+	// Taken before appointments are deleted, so Restore finds them
+	deletedAt := time.Now().Truncate(time.Millisecond).UTC()
+
+	if r.appointments != nil {
+		appointments, err := r.patientAppointments(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !cascade {
+			now := time.Now()
+			for _, apt := range appointments {
+				if apt.IsOpen() && apt.End().After(now) {
+					return repository.ErrHasDependents
+				}
+			}
+		}
+		for _, apt := range appointments {
+			if err := r.appointments.Delete(ctx, apt.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+		}
+	}
+
+	patient.DeletedAt = deletedAt
+	patient.DeletedBy = repository.ActorFrom(ctx)
+	r.patients[id] = patient
+	return nil
+}
+
+// Restore undeletes patient and their appointments deleted since
+func (r *PatientRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	patient, ok := r.patients[id]
+	if !ok || !patient.IsDeleted() {
+		return repository.ErrNotFound
+	}
+
+	if r.appointments != nil {
+		appointments, err := r.patientAppointments(repository.WithDeleted(ctx), id)
+		if err != nil {
+			return err
+		}
+		for _, apt := range appointments {
+			if !apt.IsDeleted() || apt.DeletedAt.Before(patient.DeletedAt) {
+				continue
+			}
+			err := r.appointments.Restore(ctx, apt.ID)
+			if errors.Is(err, repository.ErrConflict) {
+				continue // Slot taken meanwhile
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	patient.DeletedAt, patient.DeletedBy = time.Time{}, ""
+	r.patients[id] = patient
+	return nil
+}
+
+// Purge removes patients deleted before cutoff with their appointments
+func (r *PatientRepo) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged []primitive.ObjectID
+	for id, patient := range r.patients {
+		if patient.IsDeleted() && patient.DeletedAt.Before(cutoff) {
+			purged = append(purged, id)
+		}
+	}
+
+	for _, id := range purged {
+		// Appointments are hard-deleted only through the concrete repository
+		if appointments, ok := r.appointments.(*AppointmentRepo); ok {
+			appointments.purgePatient(id)
+		}
+		delete(r.patients, id)
+		r.order = slices.DeleteFunc(r.order, func(oid primitive.ObjectID) bool { return oid == id })
+	}
+	return len(purged), nil
+}
+
+// Erase anonymizes patient. No reminders are kept in memory.
+func (r *PatientRepo) Erase(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	patient, ok := r.patients[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	patient.Erase(time.Now())
+	r.patients[id] = patient
+	return nil
+}
+
+// patientAppointments collects all appointments of patient visible with ctx
+func (r *PatientRepo) patientAppointments(ctx context.Context, id primitive.ObjectID) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	list := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return r.appointments.ListByPatient(ctx, id, opts)
+	}
+	for apt, err := range repository.StreamAppointments(ctx, repository.ListOptions{}, list) {
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, apt)
+	}
+	return appointments, nil
+}
+
+// filter returns copies of matching patients visible with ctx, ordered by
+// name, then insertion
+func (r *PatientRepo) filter(ctx context.Context, match func(*domain.Patient) bool, page repository.Page) []*domain.Patient {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	includeDeleted := repository.IncludesDeleted(ctx)
+	var out []*domain.Patient
+	for _, id := range r.order {
+		patient := r.patients[id]
+		if (includeDeleted || !patient.IsDeleted()) && match(&patient) {
+			out = append(out, &patient)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return domain.NormalizeName(out[i].Name) < domain.NormalizeName(out[j].Name)
+	})
+
+	if page.Offset >= len(out) {
+		return nil
+	}
+	out = out[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(out) {
+		out = out[:page.Limit]
+	}
+	return out
+}
+
+// phoneTaken reports whether another patient has phone. Caller holds lock.
+func (r *PatientRepo) phoneTaken(phone string, self primitive.ObjectID) bool {
+	for id, p := range r.patients {
+		if id != self && p.Phone == phone {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReminderRepo implements repository.ReminderRepository in memory.
+// A reminder is unique per appointment, start and offset, like the MongoDB index.
+type ReminderRepo struct {
+	mu        sync.Mutex
+	reminders map[primitive.ObjectID]domain.Reminder
+	order     []primitive.ObjectID // Insertion order
+}
+
+// NewReminderRepository creates a new in-memory reminder repository
+func NewReminderRepository() repository.ReminderRepository {
+	return &ReminderRepo{reminders: make(map[primitive.ObjectID]domain.Reminder)}
+}
+
+// Create inserts a new reminder
+func (r *ReminderRepo) Create(ctx context.Context, reminder *domain.Reminder) error {
+	if err := reminder.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := reminder.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+	if _, exists := r.reminders[id]; exists {
+		return repository.ErrDuplicate
+	}
+	for _, other := range r.reminders {
+		if other.Appointment == reminder.Appointment && other.AppointmentAt.Equal(reminder.AppointmentAt) &&
+			other.Offset == reminder.Offset {
+			return repository.ErrDuplicate
+		}
+	}
+
+	reminder.ID = id
+	r.reminders[id] = *reminder
+	r.order = append(r.order, id)
+	return nil
+}
+
+// MarkSent records delivery of reminder
+func (r *ReminderRepo) MarkSent(ctx context.Context, id primitive.ObjectID, messageID string, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reminder, ok := r.reminders[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	reminder.MessageID = messageID
+	reminder.SentAt = sentAt
+	r.reminders[id] = reminder
+	return nil
+}
+
+// Delete removes reminder by ID
+func (r *ReminderRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.reminders[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.reminders, id)
+	for i, other := range r.order {
+		if other == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ListByAppointment retrieves reminders for appointment
+func (r *ReminderRepo) ListByAppointment(ctx context.Context, appointmentID primitive.ObjectID) ([]*domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var reminders []*domain.Reminder
+	for _, id := range r.order {
+		if reminder := r.reminders[id]; reminder.Appointment == appointmentID {
+			reminders = append(reminders, &reminder)
+		}
+	}
+	return reminders, nil
+}
+
+// GetByMessageID retrieves reminder by sent WhatsApp message ID
+func (r *ReminderRepo) GetByMessageID(ctx context.Context, messageID string) (*domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.order {
+		if reminder := r.reminders[id]; reminder.MessageID != "" && reminder.MessageID == messageID {
+			return &reminder, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// LatestByPatient retrieves most recently sent reminder for an upcoming appointment
+func (r *ReminderRepo) LatestByPatient(ctx context.Context, patientID primitive.ObjectID, after time.Time) (*domain.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var latest *domain.Reminder
+	for _, id := range r.order {
+		reminder := r.reminders[id]
+		if reminder.Patient != patientID || !reminder.AppointmentAt.After(after) || reminder.SentAt.IsZero() {
+			continue
+		}
+		if latest == nil || reminder.SentAt.After(latest.SentAt) {
+			latest = &reminder
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+	return latest, nil
+}
@@ -0,0 +1,79 @@
+// Package memory provides in-memory repository implementations.
+// Data is lost on restart; intended for tests and single-process runs.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+)
+
+// SessionRepo implements repository.SessionRepository in memory
+type SessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]domain.Session
+	ttl      time.Duration
+}
+
+// NewSessionRepository creates a new in-memory session repository
+func NewSessionRepository(ttl time.Duration) repository.SessionRepository {
+	return &SessionRepo{sessions: make(map[string]domain.Session), ttl: ttl}
+}
+
+// Get retrieves non-expired session by ID
+func (r *SessionRepo) Get(ctx context.Context, id string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if session.Expired(time.Now()) {
+		delete(r.sessions, id)
+		return nil, repository.ErrNotFound
+	}
+
+	return cloneSession(&session), nil
+}
+
+// Save upserts session, refreshing activity and expiry
+func (r *SessionRepo) Save(ctx context.Context, session *domain.Session) error {
+	if err := session.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	session.LastActivity = now
+	session.ExpiresAt = now.Add(r.ttl)
+	r.sessions[session.ID] = *cloneSession(session)
+	return nil
+}
+
+// Delete removes session by ID
+func (r *SessionRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.sessions, id)
+	return nil
+}
+
+// cloneSession copies session so callers never share the slots map
+func cloneSession(s *domain.Session) *domain.Session {
+	c := *s
+	c.Slots = make(map[string]string, len(s.Slots))
+	for k, v := range s.Slots {
+		c.Slots[k] = v
+	}
+	return &c
+}
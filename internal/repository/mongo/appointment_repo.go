@@ -12,30 +12,87 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// AppointmentRepo implements repository.AppointmentRepository for MongoDB
+// AppointmentRepo implements repository.AppointmentRepository for MongoDB.
+// Non-cancelled, non-deleted appointments hold slot claims so overlapping
+// bookings are rejected atomically with repository.ErrConflict.
 type AppointmentRepo struct {
-	coll *mongo.Collection
+	coll      *mongo.Collection
+	reminders *mongo.Collection
+	claims    *slotClaims
 }
 
 // NewAppointmentRepository creates a new MongoDB appointment repository
 func NewAppointmentRepository(db *mongo.Database) repository.AppointmentRepository {
-	return &AppointmentRepo{coll: db.Collection("appointments")}
+	return newAppointmentRepo(db)
+}
+
+// newAppointmentRepo creates appointment repository for use within the package
+func newAppointmentRepo(db *mongo.Database) *AppointmentRepo {
+	return &AppointmentRepo{
+		coll:      db.Collection("appointments"),
+		reminders: db.Collection("reminders"),
+		claims:    &slotClaims{coll: db.Collection("appointment_slots")},
+	}
 }
 
 // Create inserts a new appointment
-func (r *AppointmentRepo) Create(ctx context.Context, apt *domain.Appointment) error {
+func (r *AppointmentRepo) Create(ctx context.Context, apt *domain.Appointment) (err error) {
 	if err := apt.Validate(); err != nil {
 		return repository.ErrInvalidInput
 	}
 
-	result, err := r.coll.InsertOne(ctx, apt)
-	if err != nil {
+	if apt.ID.IsZero() {
+		// ID is assigned up front so slot claims can reference it
+		apt.ID = primitive.NewObjectID()
+		defer func() {
+			if err != nil {
+				apt.ID = primitive.NilObjectID
+			}
+		}()
+	} else {
+		// Claims of an existing appointment with this ID must not be touched
+		count, err := r.coll.CountDocuments(ctx, bson.M{"_id": apt.ID}, options.Count().SetLimit(1))
+		if err != nil {
+			return fmt.Errorf("failed to check appointment id: %w", err)
+		}
+		if count > 0 {
+			return repository.ErrDuplicate
+		}
+	}
+
+	if apt.CreatedAt.IsZero() {
+		apt.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+		defer func() {
+			if err != nil {
+				apt.CreatedAt = time.Time{}
+			}
+		}()
+	}
+
+	var claimed []time.Time
+	if apt.BlocksAgenda() {
+		if err := r.checkOverlap(ctx, apt); err != nil {
+			return err
+		}
+		claimed = claimBuckets(apt.DateTime, apt.End())
+		if err := r.claims.claim(ctx, apt.ID, claimed); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.coll.InsertOne(ctx, apt); err != nil {
+		if relErr := r.claims.release(context.WithoutCancel(ctx), apt.ID, claimed); relErr != nil {
+			log.Error().Err(relErr).Str("appointment_id", apt.ID.Hex()).Msg("failed to release slot claims")
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrDuplicate
+		}
 		return fmt.Errorf("failed to create appointment: %w", err)
 	}
 
-	apt.ID = result.InsertedID.(primitive.ObjectID)
 	log.Info().Str("appointment_id", apt.ID.Hex()).Msg("appointment created successfully")
 	return nil
 }
@@ -43,7 +100,7 @@ func (r *AppointmentRepo) Create(ctx context.Context, apt *domain.Appointment) e
 // GetByID retrieves appointment by ID
 func (r *AppointmentRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Appointment, error) {
 	var apt domain.Appointment
-	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&apt)
+	err := r.coll.FindOne(ctx, visible(ctx, bson.M{"_id": id})).Decode(&apt)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, repository.ErrNotFound
@@ -53,114 +110,406 @@ func (r *AppointmentRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*
 	return &apt, nil
 }
 
-// List retrieves all appointments
-func (r *AppointmentRepo) List(ctx context.Context) ([]*domain.Appointment, error) {
-	cursor, err := r.coll.Find(ctx, bson.M{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list appointments: %w", err)
-	}
-	defer cursor.Close(ctx)
+// List retrieves a page of all appointments
+func (r *AppointmentRepo) List(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{}, opts)
+}
 
-	var appointments []*domain.Appointment
-	if err := cursor.All(ctx, &appointments); err != nil {
-		return nil, fmt.Errorf("failed to decode appointments: %w", err)
+// Find retrieves a page of appointments matching filter
+func (r *AppointmentRepo) Find(ctx context.Context, filter repository.AppointmentFilter, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	page, err := r.page(ctx, visible(ctx, findFilter(filter)), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointments: %w", err)
 	}
-
-	return appointments, nil
+	return page, nil
 }
 
-// Update updates existing appointment
+// Update updates existing appointment.
+// Status changes must be allowed transitions (returns *domain.TransitionError);
+// returns repository.ErrConflict if status changed concurrently.
 func (r *AppointmentRepo) Update(ctx context.Context, apt *domain.Appointment) error {
 	if err := apt.Validate(); err != nil {
 		return repository.ErrInvalidInput
 	}
 
-	filter := bson.M{"_id": apt.ID}
-	update := bson.M{"$set": bson.M{
-		"datetime": apt.DateTime,
-		"patient":  apt.Patient,
-		"status":   apt.Status,
-	}}
+	current, err := r.currentStatus(ctx, apt.ID)
+	if err != nil {
+		return err
+	}
+	if current != apt.Status {
+		if err := domain.CheckTransition(current, apt.Status); err != nil {
+			return err
+		}
+	}
 
-	result, err := r.coll.UpdateOne(ctx, filter, update)
+	owned, err := r.claims.owned(ctx, apt.ID)
 	if err != nil {
-		return fmt.Errorf("failed to update appointment: %w", err)
+		return err
 	}
 
-	if result.MatchedCount == 0 {
-		return repository.ErrNotFound
+	var want []time.Time
+	if apt.BlocksAgenda() {
+		if err := r.checkOverlap(ctx, apt); err != nil {
+			return err
+		}
+		want = claimBuckets(apt.DateTime, apt.End())
+	}
+
+	add, remove := diffBuckets(owned, want)
+	if err := r.claims.claim(ctx, apt.ID, add); err != nil {
+		return err
+	}
+
+	// Only apply if status is still what the transition was checked against
+	filter := bson.M{"_id": apt.ID, "status": current, "deleted_at": nil}
+	set := bson.M{
+		"datetime":       apt.DateTime,
+		"end_datetime":   apt.EndTime,
+		"type":           apt.Type,
+		"patient":        apt.Patient,
+		"professional":   apt.Professional,
+		"status":         apt.Status,
+		"status_history": apt.StatusHistory,
+	}
+	// Lineage links are only ever added
+	if !apt.RescheduledFrom.IsZero() {
+		set["rescheduled_from"] = apt.RescheduledFrom
+	}
+	if !apt.RescheduledTo.IsZero() {
+		set["rescheduled_to"] = apt.RescheduledTo
+	}
+	update := bson.M{"$set": set}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil || result.MatchedCount == 0 {
+		if relErr := r.claims.release(context.WithoutCancel(ctx), apt.ID, add); relErr != nil {
+			log.Error().Err(relErr).Str("appointment_id", apt.ID.Hex()).Msg("failed to release slot claims")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update appointment: %w", err)
+		}
+		return repository.ErrConflict
+	}
+
+	if err := r.claims.release(ctx, apt.ID, remove); err != nil {
+		return err
 	}
 
 	log.Info().Str("appointment_id", apt.ID.Hex()).Msg("appointment updated successfully")
 	return nil
 }
 
-// Delete removes appointment by ID
+// undoAttempts bounds tries at removing the new appointment of a failed
+// reschedule; undoBackoff is the wait before the first retry, doubled after.
+const (
+	undoAttempts = 3
+	undoBackoff  = 100 * time.Millisecond
+)
+
+// Reschedule creates next, then updates orig. MongoDB writes are not
+// transactional here, so if the update fails next is removed again with its
+// slot claims. If that removal keeps failing, its error is returned along
+// with the update's.
+func (r *AppointmentRepo) Reschedule(ctx context.Context, orig, next *domain.Appointment) error {
+	if err := orig.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	if err := r.Create(ctx, next); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, orig); err != nil {
+		if undoErr := r.undoCreate(ctx, next.ID); undoErr != nil {
+			return errors.Join(err, undoErr)
+		}
+		return err
+	}
+
+	log.Info().
+		Str("appointment_id", next.ID.Hex()).
+		Str("rescheduled_from", orig.ID.Hex()).
+		Msg("appointment rescheduled successfully")
+	return nil
+}
+
+// undoCreate purges appointment id with its slot claims, retrying with
+// backoff. Runs even if ctx is cancelled: a leftover would hold the slot.
+func (r *AppointmentRepo) undoCreate(ctx context.Context, id primitive.ObjectID) error {
+	ctx = context.WithoutCancel(ctx)
+	wait := undoBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if _, err = r.purge(ctx, bson.M{"_id": id}); err == nil {
+			return nil
+		}
+		if attempt == undoAttempts {
+			break
+		}
+		log.Warn().Err(err).Str("appointment_id", id.Hex()).Int("attempt", attempt).Msg("retrying removal of rescheduled appointment")
+		time.Sleep(wait)
+		wait *= 2
+	}
+
+	log.Error().Err(err).Str("appointment_id", id.Hex()).Msg("failed to remove rescheduled appointment")
+	return fmt.Errorf("failed to undo rescheduled appointment: %w", err)
+}
+
+// Delete soft-deletes appointment by ID and releases its slot claims
 func (r *AppointmentRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
-	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now(), "deleted_by": repository.ActorFrom(ctx)}}
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, update)
 	if err != nil {
 		return fmt.Errorf("failed to delete appointment: %w", err)
 	}
 
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		return repository.ErrNotFound
 	}
 
+	if err := r.claims.releaseAll(ctx, id); err != nil {
+		return err
+	}
+
 	log.Info().Str("appointment_id", id.Hex()).Msg("appointment deleted successfully")
 	return nil
 }
 
-// ListByPatient retrieves appointments for patient
-func (r *AppointmentRepo) ListByPatient(ctx context.Context, patientID primitive.ObjectID) ([]*domain.Appointment, error) {
-	cursor, err := r.coll.Find(ctx, bson.M{"patient": patientID})
+// Restore undeletes appointment by ID, reclaiming its slot
+func (r *AppointmentRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	var apt domain.Appointment
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&apt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list appointments by patient: %w", err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("failed to get deleted appointment: %w", err)
 	}
-	defer cursor.Close(ctx)
 
-	var appointments []*domain.Appointment
-	if err := cursor.All(ctx, &appointments); err != nil {
-		return nil, fmt.Errorf("failed to decode appointments by patient: %w", err)
+	if err := r.restore(ctx, &apt); err != nil {
+		return err
+	}
+
+	log.Info().Str("appointment_id", id.Hex()).Msg("appointment restored successfully")
+	return nil
+}
+
+// restore undeletes stored deleted apt. Returns repository.ErrConflict if
+// its slot was taken, or it was restored or purged concurrently.
+func (r *AppointmentRepo) restore(ctx context.Context, apt *domain.Appointment) error {
+	var claimed []time.Time
+	if apt.BlocksAgenda() {
+		if err := r.checkOverlap(ctx, apt); err != nil {
+			return err
+		}
+		claimed = claimBuckets(apt.DateTime, apt.End())
+		if err := r.claims.claim(ctx, apt.ID, claimed); err != nil {
+			return err
+		}
 	}
 
-	return appointments, nil
+	filter := bson.M{"_id": apt.ID, "deleted_at": apt.DeletedAt}
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}}
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil || result.MatchedCount == 0 {
+		if relErr := r.claims.release(context.WithoutCancel(ctx), apt.ID, claimed); relErr != nil {
+			log.Error().Err(relErr).Str("appointment_id", apt.ID.Hex()).Msg("failed to release slot claims")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore appointment: %w", err)
+		}
+		return repository.ErrConflict
+	}
+	return nil
 }
 
-// ListByDateRange retrieves appointments in date range
-func (r *AppointmentRepo) ListByDateRange(ctx context.Context, start, end time.Time) ([]*domain.Appointment, error) {
-	filter := bson.M{
-		"datetime": bson.M{
-			"$gte": start,
-			"$lte": end,
-		},
+// Purge removes appointments deleted before cutoff with their reminders
+func (r *AppointmentRepo) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	purged, err := r.purge(ctx, bson.M{"deleted_at": bson.M{"$lt": cutoff}})
+	if err != nil || purged == 0 {
+		return purged, err
 	}
 
-	cursor, err := r.coll.Find(ctx, filter)
+	log.Info().Int("appointments", purged).Time("cutoff", cutoff).Msg("purged deleted appointments")
+	return purged, nil
+}
+
+// purge removes appointments matching filter with their reminders and any
+// leftover slot claims. Dependents go first so a failed purge can be retried.
+func (r *AppointmentRepo) purge(ctx context.Context, filter bson.M) (int, error) {
+	ids, err := r.coll.Distinct(ctx, "_id", filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list appointments by date range: %w", err)
+		return 0, fmt.Errorf("failed to list appointments to purge: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
 	}
-	defer cursor.Close(ctx)
 
-	var appointments []*domain.Appointment
-	if err := cursor.All(ctx, &appointments); err != nil {
-		return nil, fmt.Errorf("failed to decode appointments by date range: %w", err)
+	if _, err := r.reminders.DeleteMany(ctx, bson.M{"appointment": bson.M{"$in": ids}}); err != nil {
+		return 0, fmt.Errorf("failed to purge appointment reminders: %w", err)
+	}
+	if err := r.claims.deleteClaims(ctx, bson.M{"appointment": bson.M{"$in": ids}}); err != nil {
+		return 0, err
 	}
 
-	return appointments, nil
+	result, err := r.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge appointments: %w", err)
+	}
+	return int(result.DeletedCount), nil
+}
+
+// ListByPatient retrieves a page of appointments for patient
+func (r *AppointmentRepo) ListByPatient(ctx context.Context, patientID primitive.ObjectID, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Patient: patientID}, opts)
+}
+
+// ListByDateRange retrieves a page of appointments overlapping [start, end)
+func (r *AppointmentRepo) ListByDateRange(ctx context.Context, start, end time.Time, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Start: start, End: end}, opts)
 }
 
-// ListByStatus retrieves appointments by status
-func (r *AppointmentRepo) ListByStatus(ctx context.Context, status string) ([]*domain.Appointment, error) {
-	cursor, err := r.coll.Find(ctx, bson.M{"status": status})
+// ListByStatus retrieves a page of appointments by status
+func (r *AppointmentRepo) ListByStatus(ctx context.Context, status string, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Statuses: []string{status}}, opts)
+}
+
+// page retrieves one page of appointments matching filter.
+// Fetches one extra appointment to tell whether another page follows.
+func (r *AppointmentRepo) page(ctx context.Context, filter bson.M, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	pos, err := opts.Resolve()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list appointments by status: %w", err)
+		return nil, err
+	}
+
+	dir, cmp := 1, "$gt"
+	if opts.Desc {
+		dir, cmp = -1, "$lt"
+	}
+	sort := bson.D{{Key: "_id", Value: dir}}
+	if opts.Sort == repository.SortByDateTime {
+		sort = bson.D{{Key: "datetime", Value: dir}, {Key: "_id", Value: dir}}
+	}
+
+	if pos != nil {
+		after := bson.M{"_id": bson.M{cmp: pos.ID}}
+		if opts.Sort == repository.SortByDateTime {
+			after = bson.M{"$or": bson.A{
+				bson.M{"datetime": bson.M{cmp: pos.DateTime}},
+				bson.M{"datetime": pos.DateTime, "_id": bson.M{cmp: pos.ID}},
+			}}
+		}
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+
+	findOpts := options.Find().SetSort(sort).SetLimit(int64(opts.Limit + 1))
+	cursor, err := r.coll.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var appointments []*domain.Appointment
 	if err := cursor.All(ctx, &appointments); err != nil {
-		return nil, fmt.Errorf("failed to decode appointments by status: %w", err)
+		return nil, err
+	}
+
+	return opts.NewPage(appointments), nil
+}
+
+// checkOverlap returns repository.ErrConflict if another non-cancelled, live
+// appointment overlaps apt. Catches appointments stored without slot claims;
+// concurrent bookings are caught by the claims themselves.
+func (r *AppointmentRepo) checkOverlap(ctx context.Context, apt *domain.Appointment) error {
+	filter := overlapFilter(apt.DateTime, apt.End())
+	filter["_id"] = bson.M{"$ne": apt.ID}
+	filter["status"] = bson.M{"$nin": bson.A{domain.StatusCancelled, domain.StatusRescheduled}}
+	filter["deleted_at"] = nil
+
+	count, err := r.coll.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("failed to check appointment overlap: %w", err)
+	}
+	if count > 0 {
+		return repository.ErrConflict
 	}
+	return nil
+}
+
+// currentStatus returns stored status of live appointment
+func (r *AppointmentRepo) currentStatus(ctx context.Context, id primitive.ObjectID) (string, error) {
+	var stored struct {
+		Status string `bson:"status"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"status": 1})
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}, opts).Decode(&stored)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", repository.ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get appointment status: %w", err)
+	}
+	return stored.Status, nil
+}
 
-	return appointments, nil
+// findFilter translates f to a query. Equality on patient, professional or
+// status with a date range is served by the (field, ..., datetime, _id) indexes.
+func findFilter(f repository.AppointmentFilter) bson.M {
+	filter := overlapFilter(f.Start, f.End)
+
+	if !f.Patient.IsZero() {
+		filter["patient"] = f.Patient
+	}
+	if f.Professional != "" {
+		filter["professional"] = f.Professional
+	}
+	switch len(f.Statuses) {
+	case 0:
+	case 1:
+		filter["status"] = f.Statuses[0]
+	default:
+		filter["status"] = bson.M{"$in": f.Statuses}
+	}
+
+	created := bson.M{}
+	if !f.CreatedFrom.IsZero() {
+		created["$gte"] = f.CreatedFrom
+	}
+	if !f.CreatedTo.IsZero() {
+		created["$lt"] = f.CreatedTo
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+
+	return filter
+}
+
+// overlapFilter matches appointments intersecting [start, end); zero start or
+// end leaves that side open.
+// Records stored without end_datetime are assumed to last DefaultAppointmentDuration.
+func overlapFilter(start, end time.Time) bson.M {
+	filter := bson.M{}
+	if !end.IsZero() {
+		filter["datetime"] = bson.M{"$lt": end}
+	}
+	if !start.IsZero() {
+		filter["$or"] = bson.A{
+			bson.M{"end_datetime": bson.M{"$gt": start}},
+			bson.M{
+				"end_datetime": nil,
+				"datetime":     bson.M{"$gt": start.Add(-domain.DefaultAppointmentDuration)},
+			},
+		}
+	}
+	return filter
+}
+
+// visible restricts filter to live records unless ctx includes deleted ones
+// (see repository.WithDeleted).
+func visible(ctx context.Context, filter bson.M) bson.M {
+	if !repository.IncludesDeleted(ctx) {
+		filter["deleted_at"] = nil
+	}
+	return filter
 }
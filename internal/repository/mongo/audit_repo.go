@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepo implements repository.AuditRepository for MongoDB.
+// The audit_log collection is only ever inserted into; restrict the
+// application's database role to insert and find on it to enforce that.
+type AuditRepo struct {
+	coll *mongo.Collection
+}
+
+// NewAuditRepository creates a new MongoDB audit repository
+func NewAuditRepository(db *mongo.Database) repository.AuditRepository {
+	return &AuditRepo{coll: db.Collection("audit_log")}
+}
+
+// Append inserts audit entry
+func (r *AuditRepo) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	if err := entry.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	result, err := r.coll.InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrDuplicate
+		}
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ListByEntity retrieves audit entries of entity, oldest first
+func (r *AuditRepo) ListByEntity(ctx context.Context, entity string, id primitive.ObjectID) ([]*domain.AuditEntry, error) {
+	filter := bson.M{"entity": entity, "entity_id": id}
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+
+	return entries, nil
+}
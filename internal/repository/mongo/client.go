@@ -61,8 +61,9 @@ func Disconnect(ctx context.Context, client *mongo.Client) error {
 	return nil
 }
 
-// EnsureIndexes creates required indexes on collections.
-func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+// ensureIndexes creates indexes of the initial schema.
+// Applied by the first migration; later index changes are new migrations.
+func ensureIndexes(ctx context.Context, db *mongo.Database) error {
 	log.Info().Msg("ensuring mongodb indexes")
 
 	// Patients: unique index on phone
@@ -108,6 +109,65 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 	}
 	log.Info().Str("index", statusIdxName).Msg("created appointments.status index")
 
+	// Appointment slots: index on owning appointment (bucket is _id, unique)
+	slotsCol := db.Collection("appointment_slots")
+	slotOwnerIdx := mongo.IndexModel{
+		Keys: bson.D{{Key: "appointment", Value: 1}},
+	}
+	slotOwnerIdxName, err := slotsCol.Indexes().CreateOne(ctx, slotOwnerIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create appointment_slots index: %w", err)
+	}
+	log.Info().Str("index", slotOwnerIdxName).Msg("created appointment_slots.appointment index")
+
+	// Reminders: unique index so each reminder is sent once
+	remindersCol := db.Collection("reminders")
+	reminderIdx := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "appointment", Value: 1},
+			{Key: "appointment_at", Value: 1},
+			{Key: "offset", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	reminderIdxName, err := remindersCol.Indexes().CreateOne(ctx, reminderIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create reminders index: %w", err)
+	}
+	log.Info().Str("index", reminderIdxName).Msg("created reminders unique index")
+
+	// Reminders: lookup by sent message (for quoted replies)
+	messageIdx := mongo.IndexModel{
+		Keys: bson.D{{Key: "message_id", Value: 1}},
+	}
+	messageIdxName, err := remindersCol.Indexes().CreateOne(ctx, messageIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create reminders message_id index: %w", err)
+	}
+	log.Info().Str("index", messageIdxName).Msg("created reminders.message_id index")
+
+	// Reminders: latest reminder per patient
+	patientSentIdx := mongo.IndexModel{
+		Keys: bson.D{{Key: "patient", Value: 1}, {Key: "sent_at", Value: -1}},
+	}
+	patientSentIdxName, err := remindersCol.Indexes().CreateOne(ctx, patientSentIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create reminders patient index: %w", err)
+	}
+	log.Info().Str("index", patientSentIdxName).Msg("created reminders.patient index")
+
+	// Sessions: TTL index on expires_at (expire at stored time)
+	sessionsCol := db.Collection("sessions")
+	expiresIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	expiresIdxName, err := sessionsCol.Indexes().CreateOne(ctx, expiresIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create expires_at index: %w", err)
+	}
+	log.Info().Str("index", expiresIdxName).Msg("created sessions.expires_at index")
+
 	log.Info().Msg("all indexes created successfully")
 	return nil
 }
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/repository/repotest"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// openTest connects to MONGO_URI and returns an empty migrated database,
+// dropped after the test. Skips the test when MONGO_URI is not set.
+func openTest(t *testing.T) *mongo.Database {
+	t.Helper()
+	db := openEmpty(t)
+	if _, err := Migrate(context.Background(), db, false); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// openEmpty is openTest without migrating.
+func openEmpty(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		t.Skip("MONGO_URI not set")
+	}
+
+	ctx := context.Background()
+	name := fmt.Sprintf("clara_test_%d", time.Now().UnixNano())
+	client, db, err := Connect(ctx, uri, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := db.Drop(context.Background()); err != nil {
+			t.Errorf("drop test database: %v", err)
+		}
+		Disconnect(context.Background(), client)
+	})
+	return db
+}
+
+func TestPatientConformance(t *testing.T) {
+	db := openTest(t)
+	if err := repotest.Patients(context.Background(), NewPatientRepository(db), NewAppointmentRepository(db)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppointmentConformance(t *testing.T) {
+	if err := repotest.Appointments(context.Background(), NewAppointmentRepository(openTest(t))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReminderConformance(t *testing.T) {
+	if err := repotest.Reminders(context.Background(), NewReminderRepository(openTest(t))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditConformance(t *testing.T) {
+	if err := repotest.Audit(context.Background(), NewAuditRepository(openTest(t))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionConformance(t *testing.T) {
+	ttl := 300 * time.Millisecond
+	if err := repotest.Sessions(context.Background(), NewSessionRepository(openTest(t), ttl), ttl); err != nil {
+		t.Fatal(err)
+	}
+}
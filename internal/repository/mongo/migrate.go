@@ -0,0 +1,249 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrMigrationLocked is returned when another instance keeps the migration lock
+// past the wait timeout.
+var ErrMigrationLocked = errors.New("migrations locked by another instance")
+
+const (
+	migrationLockID   = "migrations"
+	migrationLockTTL  = 10 * time.Minute // Lock of a crashed instance is taken over after this
+	migrationLockWait = 2 * time.Minute  // How long to wait for a running migration
+	migrationLockPoll = time.Second
+)
+
+// Migration evolves indexes or documents from one schema version to the next.
+// Up must be safe to rerun: a crash can interrupt it before it is recorded.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStatus reports whether a known migration was applied
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   time.Time // Zero while pending
+}
+
+// appliedMigration is a record in the migrations collection
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+	DurationMS  int64     `bson:"duration_ms"`
+}
+
+// migrationLock is the single document in migration_lock held while migrating
+type migrationLock struct {
+	ID        string    `bson:"_id"`
+	Owner     string    `bson:"owner"`
+	LockedAt  time.Time `bson:"locked_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// MigrationStatuses lists known migrations in order, with applied time if applied.
+// Returns error if database has migrations unknown to this build.
+func MigrationStatuses(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	cursor, err := db.Collection("migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+
+	applied := make(map[int]time.Time, len(records))
+	for _, rec := range records {
+		if rec.Version > len(migrations) {
+			return nil, fmt.Errorf("database has migration %d, newer than supported version %d", rec.Version, len(migrations))
+		}
+		applied[rec.Version] = rec.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = MigrationStatus{Version: m.Version, Description: m.Description, AppliedAt: applied[m.Version]}
+	}
+	return statuses, nil
+}
+
+// PendingMigrations returns migrations not yet applied, in order.
+func PendingMigrations(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	statuses, err := MigrationStatuses(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []MigrationStatus
+	for _, s := range statuses {
+		if s.AppliedAt.IsZero() {
+			pending = append(pending, s)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies pending migrations in order and returns them.
+// Holds the migration lock meanwhile, waiting for another instance's run to
+// finish. With dryRun, only returns what would be applied.
+func Migrate(ctx context.Context, db *mongo.Database, dryRun bool) ([]MigrationStatus, error) {
+	if dryRun {
+		return PendingMigrations(ctx, db)
+	}
+
+	owner := lockOwner()
+	if err := acquireMigrationLock(ctx, db, owner); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := releaseMigrationLock(context.WithoutCancel(ctx), db, owner); err != nil {
+			log.Error().Err(err).Msg("failed to release migration lock")
+		}
+	}()
+
+	// Read under lock: a previous holder may have applied everything
+	pending, err := PendingMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		log.Info().Msg("mongodb schema is up to date")
+		return nil, nil
+	}
+
+	applied := make([]MigrationStatus, 0, len(pending))
+	for _, p := range pending {
+		if err := refreshMigrationLock(ctx, db, owner); err != nil {
+			return applied, err
+		}
+
+		log.Info().Int("version", p.Version).Str("description", p.Description).Msg("applying mongodb migration")
+		start := time.Now()
+		if err := migrations[p.Version-1].Up(ctx, db); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d: %w", p.Version, err)
+		}
+
+		record := appliedMigration{
+			Version:     p.Version,
+			Description: p.Description,
+			AppliedAt:   time.Now(),
+			DurationMS:  time.Since(start).Milliseconds(),
+		}
+		if _, err := db.Collection("migrations").InsertOne(ctx, record); err != nil {
+			return applied, fmt.Errorf("failed to record migration %d: %w", p.Version, err)
+		}
+
+		p.AppliedAt = record.AppliedAt
+		applied = append(applied, p)
+		log.Info().Int("version", p.Version).Int64("duration_ms", record.DurationMS).Msg("applied mongodb migration")
+	}
+
+	return applied, nil
+}
+
+// ForceUnlock removes the migration lock, e.g. one left by a crashed instance.
+func ForceUnlock(ctx context.Context, db *mongo.Database) error {
+	result, err := db.Collection("migration_lock").DeleteOne(ctx, bson.M{"_id": migrationLockID})
+	if err != nil {
+		return fmt.Errorf("failed to remove migration lock: %w", err)
+	}
+	log.Warn().Int64("removed", result.DeletedCount).Msg("migration lock removed")
+	return nil
+}
+
+// acquireMigrationLock inserts the lock document, taking over an expired one.
+// Polls while another owner holds it, up to migrationLockWait.
+func acquireMigrationLock(ctx context.Context, db *mongo.Database, owner string) error {
+	coll := db.Collection("migration_lock")
+	deadline := time.Now().Add(migrationLockWait)
+	waiting := false
+
+	for {
+		now := time.Now()
+		lock := migrationLock{ID: migrationLockID, Owner: owner, LockedAt: now, ExpiresAt: now.Add(migrationLockTTL)}
+
+		_, err := coll.InsertOne(ctx, lock)
+		if err == nil {
+			log.Debug().Str("owner", owner).Msg("migration lock acquired")
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+
+		// Only one instance can match an expired lock; the replacement is not expired
+		filter := bson.M{"_id": migrationLockID, "expires_at": bson.M{"$lte": now}}
+		result, err := coll.ReplaceOne(ctx, filter, lock)
+		if err != nil {
+			return fmt.Errorf("failed to take over migration lock: %w", err)
+		}
+		if result.MatchedCount > 0 {
+			log.Warn().Str("owner", owner).Msg("took over expired migration lock")
+			return nil
+		}
+
+		if now.After(deadline) {
+			return ErrMigrationLocked
+		}
+		if !waiting {
+			log.Info().Msg("waiting for migrations running in another instance")
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(migrationLockPoll):
+		}
+	}
+}
+
+// refreshMigrationLock extends lock expiry; fails if lock was taken over.
+func refreshMigrationLock(ctx context.Context, db *mongo.Database, owner string) error {
+	filter := bson.M{"_id": migrationLockID, "owner": owner}
+	update := bson.M{"$set": bson.M{"expires_at": time.Now().Add(migrationLockTTL)}}
+
+	result, err := db.Collection("migration_lock").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to refresh migration lock: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("migration lock lost: %w", ErrMigrationLocked)
+	}
+	return nil
+}
+
+// releaseMigrationLock deletes lock if still held by owner.
+func releaseMigrationLock(ctx context.Context, db *mongo.Database, owner string) error {
+	_, err := db.Collection("migration_lock").DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": owner})
+	if err != nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+	return nil
+}
+
+// lockOwner identifies this process in the lock document
+func lockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s/%d/%s", host, os.Getpid(), primitive.NewObjectID().Hex())
+}
@@ -0,0 +1,245 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// countDocs returns number of documents in collection name.
+func countDocs(t *testing.T, db *mongo.Database, name string) int64 {
+	t.Helper()
+	n, err := db.Collection(name).CountDocuments(context.Background(), bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	ctx := context.Background()
+	db := openEmpty(t)
+
+	pending, err := Migrate(ctx, db, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != len(migrations) {
+		t.Fatalf("dry run: got %d pending, want %d", len(pending), len(migrations))
+	}
+	for i, p := range pending {
+		if p.Version != i+1 || !p.AppliedAt.IsZero() {
+			t.Errorf("dry run: pending[%d] = version %d applied %s", i, p.Version, p.AppliedAt)
+		}
+	}
+	if n := countDocs(t, db, "migrations"); n != 0 {
+		t.Errorf("dry run recorded %d migrations", n)
+	}
+	if n := countDocs(t, db, "migration_lock"); n != 0 {
+		t.Errorf("dry run took the lock")
+	}
+
+	applied, err := Migrate(ctx, db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("migrate: applied %d, want %d", len(applied), len(migrations))
+	}
+
+	if pending, err := Migrate(ctx, db, true); err != nil || len(pending) != 0 {
+		t.Errorf("dry run after migrate: got %d pending, error %v", len(pending), err)
+	}
+	if applied, err := Migrate(ctx, db, false); err != nil || len(applied) != 0 {
+		t.Errorf("migrate again: applied %d, error %v", len(applied), err)
+	}
+	statuses, err := MigrationStatuses(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt.IsZero() {
+			t.Errorf("migration %d not recorded as applied", s.Version)
+		}
+	}
+}
+
+func TestMigrateTakesOverExpiredLock(t *testing.T) {
+	ctx := context.Background()
+	db := openEmpty(t)
+	locks := db.Collection("migration_lock")
+
+	// Lock of an instance that crashed more than migrationLockTTL ago
+	now := time.Now()
+	crashed := migrationLock{ID: migrationLockID, Owner: "crashed", LockedAt: now.Add(-migrationLockTTL - time.Minute), ExpiresAt: now.Add(-time.Minute)}
+	if _, err := locks.InsertOne(ctx, crashed); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := Migrate(ctx, db, false)
+	if err != nil {
+		t.Fatalf("migrate with expired lock: %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("migrate with expired lock: applied %d, want %d", len(applied), len(migrations))
+	}
+	if n := countDocs(t, db, "migration_lock"); n != 0 {
+		t.Errorf("lock not released after migrating")
+	}
+}
+
+func TestMigrateWaitsForLiveLock(t *testing.T) {
+	ctx := context.Background()
+	db := openEmpty(t)
+	locks := db.Collection("migration_lock")
+
+	now := time.Now()
+	running := migrationLock{ID: migrationLockID, Owner: "running", LockedAt: now, ExpiresAt: now.Add(migrationLockTTL)}
+	if _, err := locks.InsertOne(ctx, running); err != nil {
+		t.Fatal(err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := Migrate(waitCtx, db, false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("migrate with live lock: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := countDocs(t, db, "migrations"); n != 0 {
+		t.Errorf("applied %d migrations without the lock", n)
+	}
+
+	var lock migrationLock
+	if err := locks.FindOne(ctx, bson.M{"_id": migrationLockID}).Decode(&lock); err != nil || lock.Owner != "running" {
+		t.Errorf("live lock: got owner %q, error %v, want kept by running", lock.Owner, err)
+	}
+	if err := refreshMigrationLock(ctx, db, "other"); !errors.Is(err, ErrMigrationLocked) {
+		t.Errorf("refresh by non-owner: got %v, want %v", err, ErrMigrationLocked)
+	}
+	if err := releaseMigrationLock(ctx, db, "other"); err != nil || countDocs(t, db, "migration_lock") != 1 {
+		t.Errorf("release by non-owner removed the lock (error %v)", err)
+	}
+}
+
+func TestMigrateRejectsNewerDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := openTest(t)
+
+	future := appliedMigration{Version: len(migrations) + 1, Description: "from a newer build", AppliedAt: time.Now()}
+	if _, err := db.Collection("migrations").InsertOne(ctx, future); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MigrationStatuses(ctx, db); err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("statuses: got %v, want newer database error", err)
+	}
+	if _, err := Migrate(ctx, db, true); err == nil {
+		t.Error("dry run: expected newer database error")
+	}
+	if _, err := Migrate(ctx, db, false); err == nil {
+		t.Error("migrate: expected newer database error")
+	}
+	if n := countDocs(t, db, "migration_lock"); n != 0 {
+		t.Errorf("lock not released after failed migrate")
+	}
+}
+
+func TestMigrationsRerun(t *testing.T) {
+	ctx := context.Background()
+	db := openEmpty(t)
+	appointments := db.Collection("appointments")
+
+	// Appointments as stored before end time, type, history and creation time
+	start := time.Date(2030, 1, 7, 14, 0, 0, 0, time.UTC)
+	legacy, overlapping, cancelled := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	current := primitive.NewObjectID()
+	history := bson.A{
+		bson.M{"from": "", "to": domain.StatusPending, "at": start.Add(-48 * time.Hour), "actor": domain.ActorPatient},
+		bson.M{"from": domain.StatusPending, "to": domain.StatusConfirmed, "at": start.Add(-24 * time.Hour), "actor": domain.ActorPatient},
+	}
+	docs := []any{
+		bson.M{"_id": legacy, "patient": primitive.NewObjectID(), "datetime": start, "status": domain.StatusConfirmed},
+		bson.M{"_id": overlapping, "patient": primitive.NewObjectID(), "datetime": start.Add(30 * time.Minute), "status": domain.StatusPending},
+		bson.M{"_id": cancelled, "patient": primitive.NewObjectID(), "datetime": start, "status": domain.StatusCancelled},
+		bson.M{"_id": current, "patient": primitive.NewObjectID(), "datetime": start.Add(2 * time.Hour),
+			"end_datetime": start.Add(150 * time.Minute), "type": "retorno", "status": domain.StatusConfirmed,
+			"status_history": history, "created_at": start.Add(-48 * time.Hour)},
+	}
+	if _, err := appointments.InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	// As if each run crashed before being recorded
+	for _, m := range migrations {
+		for run := 1; run <= 2; run++ {
+			if err := m.Up(ctx, db); err != nil {
+				t.Fatalf("migration %d, run %d: %v", m.Version, run, err)
+			}
+		}
+	}
+
+	get := func(id primitive.ObjectID) *domain.Appointment {
+		t.Helper()
+		var apt domain.Appointment
+		if err := appointments.FindOne(ctx, bson.M{"_id": id}).Decode(&apt); err != nil {
+			t.Fatal(err)
+		}
+		return &apt
+	}
+
+	apt := get(legacy)
+	if want := start.Add(domain.DefaultAppointmentDuration); !apt.EndTime.Equal(want) {
+		t.Errorf("end_datetime: got %s, want %s", apt.EndTime, want)
+	}
+	if apt.Type != domain.DefaultAppointmentType {
+		t.Errorf("type: got %q, want %q", apt.Type, domain.DefaultAppointmentType)
+	}
+	if !apt.CreatedAt.Equal(legacy.Timestamp()) {
+		t.Errorf("created_at: got %s, want %s", apt.CreatedAt, legacy.Timestamp())
+	}
+	want := domain.StatusChange{From: "", To: domain.StatusConfirmed, At: legacy.Timestamp(), Actor: domain.ActorSystem, Reason: "migrated"}
+	if len(apt.StatusHistory) != 1 || apt.StatusHistory[0].To != want.To || apt.StatusHistory[0].Actor != want.Actor ||
+		apt.StatusHistory[0].Reason != want.Reason || !apt.StatusHistory[0].At.Equal(want.At) {
+		t.Errorf("status_history: got %+v, want [%+v]", apt.StatusHistory, want)
+	}
+	if err := apt.Validate(); err != nil {
+		t.Errorf("backfilled appointment invalid: %v", err)
+	}
+
+	// Fields already set are kept
+	apt = get(current)
+	if !apt.EndTime.Equal(start.Add(150*time.Minute)) || apt.Type != "retorno" || len(apt.StatusHistory) != 2 ||
+		!apt.CreatedAt.Equal(start.Add(-48*time.Hour)) {
+		t.Errorf("current appointment changed: end %s type %q history %d created %s",
+			apt.EndTime, apt.Type, len(apt.StatusHistory), apt.CreatedAt)
+	}
+
+	// Of the overlapping pair only one claims its 10 buckets, whichever came first
+	claims := &slotClaims{coll: db.Collection("appointment_slots")}
+	owned := func(id primitive.ObjectID) int {
+		t.Helper()
+		buckets, err := claims.owned(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(buckets)
+	}
+	if a, b := owned(legacy), owned(overlapping); a+b != 10 || a != 0 && b != 0 {
+		t.Errorf("overlapping appointments: claimed %d and %d buckets, want 10 by one of them", a, b)
+	}
+	if n := owned(cancelled); n != 0 {
+		t.Errorf("cancelled appointment claimed %d buckets", n)
+	}
+	if n := owned(current); n != 6 {
+		t.Errorf("current appointment claimed %d buckets, want 6", n)
+	}
+	if n := countDocs(t, db, "appointment_slots"); n != 16 {
+		t.Errorf("appointment_slots: got %d claims, want 16", n)
+	}
+}
@@ -0,0 +1,241 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrations are applied in order; Version must equal index+1.
+// Never edit an applied migration, append a new one instead.
+var migrations = []Migration{
+	{Version: 1, Description: "create initial indexes", Up: ensureIndexes},
+	{Version: 2, Description: "backfill appointment end time, type and status history", Up: backfillAppointments},
+	{Version: 3, Description: "claim agenda slots of existing appointments", Up: backfillSlotClaims},
+	{Version: 4, Description: "index normalized patient names for search", Up: indexPatientNames},
+	{Version: 5, Description: "index appointment list orderings for cursor pagination", Up: indexAppointmentPages},
+	{Version: 6, Description: "backfill appointment creation time and index filter shapes", Up: indexAppointmentFilters},
+	{Version: 7, Description: "index soft-deleted patients and appointments for purge", Up: indexDeleted},
+	{Version: 8, Description: "index audit log by entity", Up: indexAuditLog},
+}
+
+// backfillAppointments fills fields added after appointments were first stored.
+// Records without end time get DefaultAppointmentDuration, matching Appointment.End.
+func backfillAppointments(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("appointments")
+
+	endResult, err := coll.UpdateMany(ctx,
+		bson.M{"end_datetime": nil},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"end_datetime": bson.M{"$add": bson.A{"$datetime", domain.DefaultAppointmentDuration.Milliseconds()}},
+		}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill end_datetime: %w", err)
+	}
+
+	typeResult, err := coll.UpdateMany(ctx,
+		bson.M{"type": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"type": domain.DefaultAppointmentType}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill type: %w", err)
+	}
+
+	// Creation time is unknown beyond the ObjectID timestamp
+	historyResult, err := coll.UpdateMany(ctx,
+		bson.M{"status_history": nil},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"status_history": bson.A{bson.M{
+				"from":   "",
+				"to":     "$status",
+				"at":     bson.M{"$toDate": "$_id"},
+				"actor":  domain.ActorSystem,
+				"reason": "migrated",
+			}},
+		}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill status_history: %w", err)
+	}
+
+	log.Info().
+		Int64("end_datetime", endResult.ModifiedCount).
+		Int64("type", typeResult.ModifiedCount).
+		Int64("status_history", historyResult.ModifiedCount).
+		Msg("backfilled appointments")
+	return nil
+}
+
+// backfillSlotClaims claims agenda buckets for appointments stored before slot
+// claims existed. Already overlapping appointments keep what they could claim
+// and are logged for the clinic to resolve.
+func backfillSlotClaims(ctx context.Context, db *mongo.Database) error {
+	claims := &slotClaims{coll: db.Collection("appointment_slots")}
+
+	filter := bson.M{"status": bson.M{"$nin": bson.A{domain.StatusCancelled, domain.StatusRescheduled}}}
+	cursor, err := db.Collection("appointments").Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list appointments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var claimed, conflicts int
+	for cursor.Next(ctx) {
+		var apt domain.Appointment
+		if err := cursor.Decode(&apt); err != nil {
+			return fmt.Errorf("failed to decode appointment: %w", err)
+		}
+
+		owned, err := claims.owned(ctx, apt.ID)
+		if err != nil {
+			return err
+		}
+		add, _ := diffBuckets(owned, claimBuckets(apt.DateTime, apt.End()))
+		if len(add) == 0 {
+			continue
+		}
+
+		err = claims.claim(ctx, apt.ID, add)
+		if errors.Is(err, repository.ErrConflict) {
+			conflicts++
+			log.Warn().
+				Str("appointment_id", apt.ID.Hex()).
+				Time("datetime", apt.DateTime).
+				Msg("appointment overlaps another, slots left unclaimed")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		claimed++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate appointments: %w", err)
+	}
+
+	log.Info().Int("claimed", claimed).Int("conflicts", conflicts).Msg("backfilled slot claims")
+	return nil
+}
+
+// indexPatientNames backfills name_normalized and indexes it for name ordering.
+func indexPatientNames(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("patients")
+
+	cursor, err := coll.Find(ctx, bson.M{"name_normalized": nil})
+	if err != nil {
+		return fmt.Errorf("failed to list patients: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var updated int
+	for cursor.Next(ctx) {
+		var patient domain.Patient
+		if err := cursor.Decode(&patient); err != nil {
+			return fmt.Errorf("failed to decode patient: %w", err)
+		}
+
+		update := bson.M{"$set": bson.M{"name_normalized": domain.NormalizeName(patient.Name)}}
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": patient.ID}, update); err != nil {
+			return fmt.Errorf("failed to backfill name_normalized: %w", err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate patients: %w", err)
+	}
+
+	nameIdx := mongo.IndexModel{
+		Keys: bson.D{{Key: "name_normalized", Value: 1}, {Key: "_id", Value: 1}},
+	}
+	nameIdxName, err := coll.Indexes().CreateOne(ctx, nameIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create name_normalized index: %w", err)
+	}
+
+	log.Info().Int("backfilled", updated).Str("index", nameIdxName).Msg("created patients.name_normalized index")
+	return nil
+}
+
+// indexAppointmentPages indexes each list filter with the (datetime, _id) page order.
+func indexAppointmentPages(ctx context.Context, db *mongo.Database) error {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "datetime", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "patient", Value: 1}, {Key: "datetime", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "datetime", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+
+	names, err := db.Collection("appointments").Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return fmt.Errorf("failed to create appointment page indexes: %w", err)
+	}
+
+	log.Info().Strs("indexes", names).Msg("created appointment page indexes")
+	return nil
+}
+
+// indexAppointmentFilters backfills created_at from the ObjectID timestamp and
+// indexes the filter combinations Find is used with: a patient's or
+// professional's appointments by status, and stale appointments by status.
+func indexAppointmentFilters(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection("appointments")
+
+	result, err := coll.UpdateMany(ctx,
+		bson.M{"created_at": nil},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"created_at": bson.M{"$toDate": "$_id"}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill created_at: %w", err)
+	}
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "patient", Value: 1}, {Key: "status", Value: 1}, {Key: "datetime", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "professional", Value: 1}, {Key: "status", Value: 1}, {Key: "datetime", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	}
+	names, err := coll.Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return fmt.Errorf("failed to create appointment filter indexes: %w", err)
+	}
+
+	log.Info().Int64("created_at", result.ModifiedCount).Strs("indexes", names).Msg("created appointment filter indexes")
+	return nil
+}
+
+// indexDeleted indexes deletion time of soft-deleted records, which the purge
+// job scans by. Sparse, since live records have no deleted_at.
+func indexDeleted(ctx context.Context, db *mongo.Database) error {
+	for _, name := range []string{"patients", "appointments"} {
+		model := mongo.IndexModel{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		}
+		idxName, err := db.Collection(name).Indexes().CreateOne(ctx, model)
+		if err != nil {
+			return fmt.Errorf("failed to create %s.deleted_at index: %w", name, err)
+		}
+		log.Info().Str("collection", name).Str("index", idxName).Msg("created deleted_at index")
+	}
+	return nil
+}
+
+// indexAuditLog indexes audit entries for listing one entity's history.
+func indexAuditLog(ctx context.Context, db *mongo.Database) error {
+	model := mongo.IndexModel{
+		Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "at", Value: 1}},
+	}
+	name, err := db.Collection("audit_log").Indexes().CreateOne(ctx, model)
+	if err != nil {
+		return fmt.Errorf("failed to create audit_log index: %w", err)
+	}
+
+	log.Info().Str("index", name).Msg("created audit_log entity index")
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/matheusmassa1/clara/internal/domain"
 	"github.com/matheusmassa1/clara/internal/repository"
@@ -11,16 +14,30 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// PatientRepo implements repository.PatientRepository for MongoDB
+// PatientRepo implements repository.PatientRepository for MongoDB.
+// Documents carry name_normalized (see domain.NormalizeName) for search.
 type PatientRepo struct {
-	coll *mongo.Collection
+	coll         *mongo.Collection
+	appointments *AppointmentRepo
+	reminders    *mongo.Collection
+}
+
+// patientDocument is a stored patient with its search key
+type patientDocument struct {
+	*domain.Patient `bson:",inline"`
+	NameNormalized  string `bson:"name_normalized"`
 }
 
 // NewPatientRepository creates a new MongoDB patient repository
 func NewPatientRepository(db *mongo.Database) repository.PatientRepository {
-	return &PatientRepo{coll: db.Collection("patients")}
+	return &PatientRepo{
+		coll:         db.Collection("patients"),
+		appointments: newAppointmentRepo(db),
+		reminders:    db.Collection("reminders"),
+	}
 }
 
 // Create inserts a new patient
@@ -29,7 +46,8 @@ func (r *PatientRepo) Create(ctx context.Context, patient *domain.Patient) error
 		return repository.ErrInvalidInput
 	}
 
-	result, err := r.coll.InsertOne(ctx, patient)
+	doc := patientDocument{Patient: patient, NameNormalized: domain.NormalizeName(patient.Name)}
+	result, err := r.coll.InsertOne(ctx, doc)
 	if err != nil {
 		// Check for duplicate key error (unique phone index)
 		if mongo.IsDuplicateKeyError(err) {
@@ -46,7 +64,7 @@ func (r *PatientRepo) Create(ctx context.Context, patient *domain.Patient) error
 // GetByID retrieves patient by ID
 func (r *PatientRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Patient, error) {
 	var patient domain.Patient
-	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&patient)
+	err := r.coll.FindOne(ctx, visible(ctx, bson.M{"_id": id})).Decode(&patient)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, repository.ErrNotFound
@@ -59,7 +77,7 @@ func (r *PatientRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*doma
 // GetByPhone retrieves patient by phone
 func (r *PatientRepo) GetByPhone(ctx context.Context, phone string) (*domain.Patient, error) {
 	var patient domain.Patient
-	err := r.coll.FindOne(ctx, bson.M{"phone": phone}).Decode(&patient)
+	err := r.coll.FindOne(ctx, visible(ctx, bson.M{"phone": phone})).Decode(&patient)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, repository.ErrNotFound
@@ -75,10 +93,11 @@ func (r *PatientRepo) Update(ctx context.Context, patient *domain.Patient) error
 		return repository.ErrInvalidInput
 	}
 
-	filter := bson.M{"_id": patient.ID}
+	filter := bson.M{"_id": patient.ID, "deleted_at": nil}
 	update := bson.M{"$set": bson.M{
-		"name":  patient.Name,
-		"phone": patient.Phone,
+		"name":            patient.Name,
+		"name_normalized": domain.NormalizeName(patient.Name),
+		"phone":           patient.Phone,
 	}}
 
 	result, err := r.coll.UpdateOne(ctx, filter, update)
@@ -97,3 +116,220 @@ func (r *PatientRepo) Update(ctx context.Context, patient *domain.Patient) error
 	log.Info().Str("patient_id", patient.ID.Hex()).Msg("patient updated successfully")
 	return nil
 }
+
+// List retrieves patients ordered by name
+func (r *PatientRepo) List(ctx context.Context, page repository.Page) ([]*domain.Patient, error) {
+	patients, err := r.find(ctx, visible(ctx, bson.M{}), page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patients: %w", err)
+	}
+	return patients, nil
+}
+
+// Search retrieves patients whose name words start with every query term
+func (r *PatientRepo) Search(ctx context.Context, query string, page repository.Page) ([]*domain.Patient, error) {
+	terms := strings.Fields(domain.NormalizeName(query))
+	if len(terms) == 0 {
+		return r.List(ctx, page)
+	}
+
+	// Each term must start a word of name_normalized
+	conditions := make(bson.A, len(terms))
+	for i, term := range terms {
+		conditions[i] = bson.M{"name_normalized": primitive.Regex{Pattern: "(^| )" + regexp.QuoteMeta(term)}}
+	}
+
+	patients, err := r.find(ctx, visible(ctx, bson.M{"$and": conditions}), page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search patients: %w", err)
+	}
+	return patients, nil
+}
+
+// Delete soft-deletes patient and their appointments, releasing slot claims.
+// Appointments go first so a failed delete can be retried.
+func (r *PatientRepo) Delete(ctx context.Context, id primitive.ObjectID, cascade bool) error {
+	count, err := r.coll.CountDocuments(ctx, bson.M{"_id": id, "deleted_at": nil}, options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("failed to check patient: %w", err)
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+
+	if !cascade {
+		upcoming := bson.M{
+			"patient":      id,
+			"status":       bson.M{"$in": bson.A{domain.StatusPending, domain.StatusConfirmed}},
+			"end_datetime": bson.M{"$gt": time.Now()},
+			"deleted_at":   nil,
+		}
+		count, err := r.appointments.coll.CountDocuments(ctx, upcoming, options.Count().SetLimit(1))
+		if err != nil {
+			return fmt.Errorf("failed to check upcoming appointments: %w", err)
+		}
+		if count > 0 {
+			return repository.ErrHasDependents
+		}
+	}
+
+	deleted := bson.M{"$set": bson.M{"deleted_at": time.Now(), "deleted_by": repository.ActorFrom(ctx)}}
+
+	ids, err := r.appointments.coll.Distinct(ctx, "_id", bson.M{"patient": id, "deleted_at": nil})
+	if err != nil {
+		return fmt.Errorf("failed to list patient appointments: %w", err)
+	}
+	if len(ids) > 0 {
+		filter := bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil}
+		if _, err := r.appointments.coll.UpdateMany(ctx, filter, deleted); err != nil {
+			return fmt.Errorf("failed to delete patient appointments: %w", err)
+		}
+		if err := r.appointments.claims.deleteClaims(ctx, bson.M{"appointment": bson.M{"$in": ids}}); err != nil {
+			return err
+		}
+	}
+
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, deleted)
+	if err != nil {
+		return fmt.Errorf("failed to delete patient: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrNotFound
+	}
+
+	log.Info().Str("patient_id", id.Hex()).Int("appointments", len(ids)).Msg("patient deleted successfully")
+	return nil
+}
+
+// Restore undeletes patient and their appointments deleted since.
+// Appointments go first so a failed restore can be retried.
+func (r *PatientRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	var patient domain.Patient
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&patient)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("failed to get deleted patient: %w", err)
+	}
+
+	filter := bson.M{"patient": id, "deleted_at": bson.M{"$gte": patient.DeletedAt}}
+	cursor, err := r.appointments.coll.Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list patient appointments: %w", err)
+	}
+	var appointments []*domain.Appointment
+	if err := cursor.All(ctx, &appointments); err != nil {
+		return fmt.Errorf("failed to decode patient appointments: %w", err)
+	}
+
+	var restored int
+	for _, apt := range appointments {
+		err := r.appointments.restore(ctx, apt)
+		if errors.Is(err, repository.ErrConflict) {
+			log.Warn().Str("appointment_id", apt.ID.Hex()).Msg("appointment slot taken, left deleted")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		restored++
+	}
+
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}}
+	if _, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to restore patient: %w", err)
+	}
+
+	log.Info().Str("patient_id", id.Hex()).Int("appointments", restored).Msg("patient restored successfully")
+	return nil
+}
+
+// Purge removes patients deleted before cutoff with all their appointments
+// and reminders. Dependents go first so a failed purge can be retried.
+func (r *PatientRepo) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lt": cutoff}}
+	ids, err := r.coll.Distinct(ctx, "_id", filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deleted patients: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if _, err := r.reminders.DeleteMany(ctx, bson.M{"patient": bson.M{"$in": ids}}); err != nil {
+		return 0, fmt.Errorf("failed to purge patient reminders: %w", err)
+	}
+	appointments, err := r.appointments.purge(ctx, bson.M{"patient": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge patients: %w", err)
+	}
+
+	log.Info().
+		Int64("patients", result.DeletedCount).
+		Int("appointments", appointments).
+		Time("cutoff", cutoff).
+		Msg("purged deleted patients")
+	return int(result.DeletedCount), nil
+}
+
+// Erase anonymizes patient and removes their reminders.
+// Reminders go first so a failed erase can be retried.
+func (r *PatientRepo) Erase(ctx context.Context, id primitive.ObjectID) error {
+	var patient domain.Patient
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&patient); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	if _, err := r.reminders.DeleteMany(ctx, bson.M{"patient": id}); err != nil {
+		return fmt.Errorf("failed to erase patient reminders: %w", err)
+	}
+
+	patient.Erase(time.Now())
+	update := bson.M{"$set": bson.M{
+		"name":            patient.Name,
+		"name_normalized": "",
+		"phone":           patient.Phone,
+		"erased_at":       patient.ErasedAt,
+	}}
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to erase patient: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrNotFound
+	}
+
+	log.Info().Str("patient_id", id.Hex()).Msg("patient erased successfully")
+	return nil
+}
+
+// find retrieves patients matching filter, ordered by name
+func (r *PatientRepo) find(ctx context.Context, filter bson.M, page repository.Page) ([]*domain.Patient, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name_normalized", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(page.Offset))
+	if page.Limit > 0 {
+		opts.SetLimit(int64(page.Limit))
+	}
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var patients []*domain.Patient
+	if err := cursor.All(ctx, &patients); err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
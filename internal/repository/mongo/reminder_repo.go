@@ -0,0 +1,128 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReminderRepo implements repository.ReminderRepository for MongoDB.
+// Uniqueness relies on the (appointment, appointment_at, offset) index.
+type ReminderRepo struct {
+	coll *mongo.Collection
+}
+
+// NewReminderRepository creates a new MongoDB reminder repository
+func NewReminderRepository(db *mongo.Database) repository.ReminderRepository {
+	return &ReminderRepo{coll: db.Collection("reminders")}
+}
+
+// Create inserts a new reminder
+func (r *ReminderRepo) Create(ctx context.Context, reminder *domain.Reminder) error {
+	if err := reminder.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	result, err := r.coll.InsertOne(ctx, reminder)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrDuplicate
+		}
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	reminder.ID = result.InsertedID.(primitive.ObjectID)
+	log.Debug().Str("reminder_id", reminder.ID.Hex()).Msg("reminder created")
+	return nil
+}
+
+// MarkSent records delivery of reminder
+func (r *ReminderRepo) MarkSent(ctx context.Context, id primitive.ObjectID, messageID string, sentAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"message_id": messageID,
+		"sent_at":    sentAt,
+	}}
+
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes reminder by ID
+func (r *ReminderRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListByAppointment retrieves reminders for appointment
+func (r *ReminderRepo) ListByAppointment(ctx context.Context, appointmentID primitive.ObjectID) ([]*domain.Reminder, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"appointment": appointmentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders by appointment: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reminders []*domain.Reminder
+	if err := cursor.All(ctx, &reminders); err != nil {
+		return nil, fmt.Errorf("failed to decode reminders by appointment: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// GetByMessageID retrieves reminder by sent WhatsApp message ID
+func (r *ReminderRepo) GetByMessageID(ctx context.Context, messageID string) (*domain.Reminder, error) {
+	var reminder domain.Reminder
+	err := r.coll.FindOne(ctx, bson.M{"message_id": messageID}).Decode(&reminder)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get reminder by message id: %w", err)
+	}
+	return &reminder, nil
+}
+
+// LatestByPatient retrieves most recently sent reminder for an upcoming appointment
+func (r *ReminderRepo) LatestByPatient(ctx context.Context, patientID primitive.ObjectID, after time.Time) (*domain.Reminder, error) {
+	filter := bson.M{
+		"patient":        patientID,
+		"appointment_at": bson.M{"$gt": after},
+		"sent_at":        bson.M{"$exists": true},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "sent_at", Value: -1}})
+
+	var reminder domain.Reminder
+	err := r.coll.FindOne(ctx, filter, opts).Decode(&reminder)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest reminder by patient: %w", err)
+	}
+	return &reminder, nil
+}
@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SessionRepo implements repository.SessionRepository for MongoDB.
+// Expired documents are removed by the TTL index on expires_at.
+type SessionRepo struct {
+	coll *mongo.Collection
+	ttl  time.Duration
+}
+
+// NewSessionRepository creates a new MongoDB session repository
+func NewSessionRepository(db *mongo.Database, ttl time.Duration) repository.SessionRepository {
+	return &SessionRepo{coll: db.Collection("sessions"), ttl: ttl}
+}
+
+// Get retrieves non-expired session by ID
+func (r *SessionRepo) Get(ctx context.Context, id string) (*domain.Session, error) {
+	// TTL monitor runs every ~60s, so filter expired documents explicitly
+	filter := bson.M{"_id": id, "expires_at": bson.M{"$gt": time.Now()}}
+
+	var session domain.Session
+	err := r.coll.FindOne(ctx, filter).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if session.Slots == nil {
+		session.Slots = make(map[string]string)
+	}
+	return &session, nil
+}
+
+// Save upserts session, refreshing activity and expiry
+func (r *SessionRepo) Save(ctx context.Context, session *domain.Session) error {
+	if err := session.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	now := time.Now()
+	session.LastActivity = now
+	session.ExpiresAt = now.Add(r.ttl)
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.coll.ReplaceOne(ctx, bson.M{"_id": session.ID}, session, opts); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	log.Debug().Str("session_id", session.ID).Str("flow", session.Flow).Msg("session saved")
+	return nil
+}
+
+// Delete removes session by ID
+func (r *SessionRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+
+	log.Debug().Str("session_id", id).Msg("session deleted")
+	return nil
+}
@@ -0,0 +1,132 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// claimStep is agenda granularity for slot claims.
+const claimStep = domain.AgendaStep
+
+// slotClaim reserves one claimStep bucket of the agenda for an appointment.
+// The bucket start is the document _id, so MongoDB's unique _id index makes
+// two appointments claiming the same bucket impossible, even concurrently.
+type slotClaim struct {
+	Bucket      time.Time          `bson:"_id"`
+	Appointment primitive.ObjectID `bson:"appointment"`
+}
+
+// slotClaims manages the appointment_slots collection.
+type slotClaims struct {
+	coll *mongo.Collection
+}
+
+// claimBuckets returns buckets covering [start, end), rounded outward to
+// whole buckets. Slots offered by the schedule start and end on claimStep, so
+// adjacent appointments share no bucket; an appointment stored off-step only
+// claims more than it needs, which never lets an overlap through.
+func claimBuckets(start, end time.Time) []time.Time {
+	var buckets []time.Time
+	for t := start.UTC().Truncate(claimStep); t.Before(end); t = t.Add(claimStep) {
+		buckets = append(buckets, t)
+	}
+	return buckets
+}
+
+// claim inserts buckets for appointment id.
+// Returns repository.ErrConflict if any bucket is taken; partial claims are rolled back.
+func (c *slotClaims) claim(ctx context.Context, id primitive.ObjectID, buckets []time.Time) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(buckets))
+	for i, b := range buckets {
+		docs[i] = slotClaim{Bucket: b, Appointment: id}
+	}
+
+	_, err := c.coll.InsertMany(ctx, docs, options.InsertMany().SetOrdered(true))
+	if err == nil {
+		return nil
+	}
+
+	// Roll back whatever got inserted before the failure
+	if relErr := c.release(context.WithoutCancel(ctx), id, buckets); relErr != nil {
+		return fmt.Errorf("failed to roll back slot claims: %w", relErr)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrConflict
+	}
+	return fmt.Errorf("failed to claim slots: %w", err)
+}
+
+// release deletes appointment's claims on given buckets.
+func (c *slotClaims) release(ctx context.Context, id primitive.ObjectID, buckets []time.Time) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+	return c.deleteClaims(ctx, bson.M{"appointment": id, "_id": bson.M{"$in": buckets}})
+}
+
+// releaseAll deletes every claim held by appointment.
+func (c *slotClaims) releaseAll(ctx context.Context, id primitive.ObjectID) error {
+	return c.deleteClaims(ctx, bson.M{"appointment": id})
+}
+
+// deleteClaims deletes claims matching filter.
+func (c *slotClaims) deleteClaims(ctx context.Context, filter bson.M) error {
+	if _, err := c.coll.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to release slots: %w", err)
+	}
+	return nil
+}
+
+// owned returns buckets currently claimed by appointment.
+func (c *slotClaims) owned(ctx context.Context, id primitive.ObjectID) ([]time.Time, error) {
+	cursor, err := c.coll.Find(ctx, bson.M{"appointment": id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slot claims: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var claims []slotClaim
+	if err := cursor.All(ctx, &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode slot claims: %w", err)
+	}
+
+	buckets := make([]time.Time, len(claims))
+	for i, cl := range claims {
+		buckets[i] = cl.Bucket
+	}
+	return buckets, nil
+}
+
+// diffBuckets returns buckets in want but not have, and in have but not want.
+func diffBuckets(have, want []time.Time) (add, remove []time.Time) {
+	haveSet := make(map[int64]bool, len(have))
+	for _, b := range have {
+		haveSet[b.UnixMilli()] = true
+	}
+	wantSet := make(map[int64]bool, len(want))
+	for _, b := range want {
+		wantSet[b.UnixMilli()] = true
+		if !haveSet[b.UnixMilli()] {
+			add = append(add, b)
+		}
+	}
+
+	for _, b := range have {
+		if !wantSet[b.UnixMilli()] {
+			remove = append(remove, b)
+		}
+	}
+	return add, remove
+}
@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestClaimBuckets(t *testing.T) {
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	brt := time.FixedZone("BRT", -3*60*60)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       []time.Time
+	}{
+		{"on steps", at(10, 0), at(10, 15), []time.Time{at(10, 0), at(10, 5), at(10, 10)}},
+		{"start rounds down", at(10, 2), at(10, 10), []time.Time{at(10, 0), at(10, 5)}},
+		{"end rounds up", at(10, 0), at(10, 7), []time.Time{at(10, 0), at(10, 5)}},
+		{"within one step", at(10, 1), at(10, 3), []time.Time{at(10, 0)}},
+		{"other zone", at(13, 0).In(brt), at(13, 10).In(brt), []time.Time{at(13, 0), at(13, 5)}},
+		{"empty", at(10, 0), at(10, 0), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claimBuckets(tt.start, tt.end); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("claimBuckets(%s, %s) = %v, want %v", tt.start.Format("15:04"), tt.end.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimBucketsAdjacent(t *testing.T) {
+	first := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	second := first.Add(55 * time.Minute)
+
+	a := claimBuckets(first, second)
+	b := claimBuckets(second, second.Add(50*time.Minute))
+	if add, _ := diffBuckets(a, b); len(add) != len(b) {
+		t.Errorf("adjacent appointments share %d buckets, want none", len(b)-len(add))
+	}
+}
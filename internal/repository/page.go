@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"iter"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page selects a window of list results
+type Page struct {
+	Offset int // Results to skip
+	Limit  int // Maximum results; 0 means no limit
+}
+
+// Sort fields for ListOptions
+const (
+	SortByDateTime = "datetime" // Appointment start (default)
+	SortByID       = "id"       // Creation order
+)
+
+// Page sizes for ListOptions
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// ListOptions pages and orders an appointment list query.
+// Ties on the sort field are broken by ID, so pages never overlap.
+type ListOptions struct {
+	Limit  int    // Page size; 0 means DefaultListLimit, capped at MaxListLimit
+	Sort   string // SortByDateTime (default) or SortByID
+	Desc   bool   // Descending order
+	Cursor string // Next of the previous page; empty for first page
+}
+
+// AppointmentPage is one page of an appointment list query
+type AppointmentPage struct {
+	Appointments []*domain.Appointment
+	Next         string // Cursor of the following page; empty on last page
+}
+
+// Position is the sort key of an appointment; a page continues after the
+// position of the previous page's last appointment.
+type Position struct {
+	DateTime time.Time
+	ID       primitive.ObjectID
+}
+
+// cursor is the encoded form of ListOptions.Cursor
+type cursor struct {
+	Sort     string `json:"s"`
+	Desc     bool   `json:"d,omitempty"`
+	DateTime int64  `json:"t,omitempty"` // Unix milliseconds
+	ID       string `json:"i"`
+}
+
+// Resolve applies defaults to opts and decodes its cursor (nil for first page).
+// Returns ErrInvalidInput for an unknown sort, or a cursor that is malformed or
+// was issued for another ordering.
+func (o *ListOptions) Resolve() (*Position, error) {
+	if o.Sort == "" {
+		o.Sort = SortByDateTime
+	}
+	if o.Sort != SortByDateTime && o.Sort != SortByID {
+		return nil, ErrInvalidInput
+	}
+	if o.Limit <= 0 {
+		o.Limit = DefaultListLimit
+	}
+	if o.Limit > MaxListLimit {
+		o.Limit = MaxListLimit
+	}
+
+	if o.Cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(o.Cursor)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != o.Sort || c.Desc != o.Desc {
+		return nil, ErrInvalidInput
+	}
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+
+	pos := &Position{ID: id}
+	if c.Sort == SortByDateTime {
+		pos.DateTime = time.UnixMilli(c.DateTime).UTC()
+	}
+	return pos, nil
+}
+
+// NewPage returns page of resolved opts from up to Limit+1 fetched appointments,
+// with Next set if there were more than Limit.
+func (o ListOptions) NewPage(fetched []*domain.Appointment) *AppointmentPage {
+	if len(fetched) <= o.Limit {
+		return &AppointmentPage{Appointments: fetched}
+	}
+
+	appointments := fetched[:o.Limit]
+	last := appointments[len(appointments)-1]
+	c := cursor{Sort: o.Sort, Desc: o.Desc, ID: last.ID.Hex()}
+	if o.Sort == SortByDateTime {
+		c.DateTime = last.DateTime.UnixMilli()
+	}
+	data, _ := json.Marshal(c) // Plain struct, cannot fail
+
+	return &AppointmentPage{Appointments: appointments, Next: base64.RawURLEncoding.EncodeToString(data)}
+}
+
+// PositionOf returns sort key of apt.
+func PositionOf(apt *domain.Appointment) Position {
+	return Position{DateTime: apt.DateTime, ID: apt.ID}
+}
+
+// Compare orders positions as resolved opts would list them.
+func (o ListOptions) Compare(a, b Position) int {
+	c := 0
+	if o.Sort == SortByDateTime {
+		c = a.DateTime.Compare(b.DateTime)
+	}
+	if c == 0 {
+		c = bytes.Compare(a.ID[:], b.ID[:])
+	}
+	if o.Desc {
+		c = -c
+	}
+	return c
+}
+
+// AppointmentLister fetches one page of an appointment list query
+type AppointmentLister func(ctx context.Context, opts ListOptions) (*AppointmentPage, error)
+
+// StreamAppointments yields every appointment of a list query, fetching pages
+// of opts.Limit as it goes. Stops after yielding the first error.
+//
+//	for apt, err := range repository.StreamAppointments(ctx, opts, func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+//		return repo.ListByStatus(ctx, domain.StatusCompleted, opts)
+//	}) {
+func StreamAppointments(ctx context.Context, opts ListOptions, list AppointmentLister) iter.Seq2[*domain.Appointment, error] {
+	return func(yield func(*domain.Appointment, error) bool) {
+		for {
+			page, err := list(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, apt := range page.Appointments {
+				if !yield(apt, nil) {
+					return
+				}
+			}
+			if page.Next == "" {
+				return
+			}
+			opts.Cursor = page.Next
+		}
+	}
+}
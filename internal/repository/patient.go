@@ -2,15 +2,35 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/matheusmassa1/clara/internal/domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// PatientRepository defines patient data access operations
+// PatientRepository defines patient data access operations.
+// Reads skip soft-deleted patients unless ctx comes from WithDeleted.
 type PatientRepository interface {
 	Create(ctx context.Context, patient *domain.Patient) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Patient, error)
 	GetByPhone(ctx context.Context, phone string) (*domain.Patient, error)
 	Update(ctx context.Context, patient *domain.Patient) error
+	// List returns patients ordered by name.
+	List(ctx context.Context, page Page) ([]*domain.Patient, error)
+	// Search returns patients ordered by name whose name has a word starting
+	// with each query term, ignoring case and accents ("jo sil" finds "João da Silva").
+	Search(ctx context.Context, query string, page Page) ([]*domain.Patient, error)
+	// Delete soft-deletes patient with their appointments, by the actor of ctx
+	// (see WithActor). Returns ErrHasDependents if patient has upcoming
+	// appointments, unless cascade is set. The phone stays taken until purge.
+	Delete(ctx context.Context, id primitive.ObjectID, cascade bool) error
+	// Restore undeletes patient and the appointments deleted since. Appointments
+	// whose slot was taken meanwhile stay deleted.
+	Restore(ctx context.Context, id primitive.ObjectID) error
+	// Purge removes patients deleted before cutoff with all their appointments
+	// and reminders. Returns number of patients removed.
+	Purge(ctx context.Context, cutoff time.Time) (int, error)
+	// Erase anonymizes patient, deleted or not (see domain.Patient.Erase), and
+	// removes their reminders. Appointments are kept for statistics.
+	Erase(ctx context.Context, id primitive.ObjectID) error
 }
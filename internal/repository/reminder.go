@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReminderRepository defines sent reminder storage.
+// Create doubles as a lock: it fails with ErrDuplicate if the same
+// reminder was already recorded, so reminders are never sent twice.
+type ReminderRepository interface {
+	// Create returns ErrDuplicate if reminder for same appointment, start and offset exists.
+	Create(ctx context.Context, reminder *domain.Reminder) error
+	// MarkSent stores WhatsApp message ID and send time.
+	MarkSent(ctx context.Context, id primitive.ObjectID, messageID string, sentAt time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	ListByAppointment(ctx context.Context, appointmentID primitive.ObjectID) ([]*domain.Reminder, error)
+	// GetByMessageID returns ErrNotFound if no reminder was sent as that message.
+	GetByMessageID(ctx context.Context, messageID string) (*domain.Reminder, error)
+	// LatestByPatient returns most recently sent reminder for an appointment
+	// starting after given time, or ErrNotFound.
+	LatestByPatient(ctx context.Context, patientID primitive.ObjectID, after time.Time) (*domain.Reminder, error)
+}
@@ -0,0 +1,377 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Appointments checks AppointmentRepository semantics on an empty repository:
+// validation, overlap conflicts (end exclusive, cancelled ignored), status
+// transitions, overlapping date ranges, cursor pagination, filters, soft
+// delete with restore and purge, reschedule leaving no trace when it fails
+// (as long as the backend can undo; see Reschedule), a single winner
+// among concurrent overlapping creates, and not-found errors.
+func Appointments(ctx context.Context, repo repository.AppointmentRepository) error {
+	c := &checker{name: "AppointmentRepository"}
+	started := time.Now().Truncate(time.Millisecond)
+
+	patient := primitive.NewObjectID()
+	base := time.Date(2030, 1, 7, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	c.expectErr("create without patient",
+		repo.Create(ctx, newAppointment(primitive.NilObjectID, at(0), 50)), repository.ErrInvalidInput)
+	bad := newAppointment(patient, at(0), 50)
+	bad.EndTime = bad.DateTime
+	c.expectErr("create with end before start", repo.Create(ctx, bad), repository.ErrInvalidInput)
+
+	// 14:00–14:50
+	first := newAppointment(patient, at(0), 50)
+	c.expectErr("create", repo.Create(ctx, first), nil)
+	if first.ID.IsZero() {
+		c.failf("create: ID not assigned")
+	}
+	if first.CreatedAt.Before(started) {
+		c.failf("create: got creation time %s, want after %s", first.CreatedAt, started)
+	}
+
+	overlapping := newAppointment(patient, at(30), 50)
+	c.expectErr("create overlapping", repo.Create(ctx, overlapping), repository.ErrConflict)
+	if !overlapping.ID.IsZero() {
+		c.failf("create overlapping: ID assigned despite failure")
+	}
+
+	// 14:50–15:40, touching first
+	adjacent := newAppointment(patient, at(50), 50)
+	adjacent.Professional = "ana"
+	c.expectErr("create adjacent", repo.Create(ctx, adjacent), nil)
+
+	cancelled := newAppointment(patient, at(10), 30)
+	_ = cancelled.Transition(domain.StatusCancelled, domain.ActorStaff, "", base)
+	c.expectErr("create cancelled overlapping", repo.Create(ctx, cancelled), nil)
+
+	dup := newAppointment(patient, at(300), 50)
+	dup.ID = first.ID
+	c.expectErr("create with existing id", repo.Create(ctx, dup), repository.ErrDuplicate)
+
+	if got, err := repo.GetByID(ctx, first.ID); err != nil {
+		c.failf("get by id: %v", err)
+	} else {
+		if !got.DateTime.Equal(first.DateTime) || !got.EndTime.Equal(first.EndTime) {
+			c.failf("get by id: got %s–%s, want %s–%s", got.DateTime, got.EndTime, first.DateTime, first.EndTime)
+		}
+		if !got.CreatedAt.Equal(first.CreatedAt) {
+			c.failf("get by id: got creation time %s, want %s", got.CreatedAt, first.CreatedAt)
+		}
+		if got.Status != first.Status || got.Type != first.Type || got.Patient != first.Patient {
+			c.failf("get by id: got %+v, want %+v", *got, *first)
+		}
+		if len(got.StatusHistory) != len(first.StatusHistory) {
+			c.failf("get by id: got %d status changes, want %d", len(got.StatusHistory), len(first.StatusHistory))
+		}
+	}
+	_, err := repo.GetByID(ctx, primitive.NewObjectID())
+	c.expectErr("get unknown id", err, repository.ErrNotFound)
+
+	// Range returns overlapping appointments of any status, ordered by start
+	c.expectRange(ctx, repo, "range inside first", at(30), at(50), first.ID, cancelled.ID)
+	c.expectRange(ctx, repo, "range touching end", at(100), at(120))
+	c.expectRange(ctx, repo, "range spanning all", at(-60), at(120), first.ID, cancelled.ID, adjacent.ID)
+
+	// Status transitions
+	confirmed := *first
+	_ = confirmed.Transition(domain.StatusConfirmed, domain.ActorPatient, "", base)
+	c.expectErr("update pending to confirmed", repo.Update(ctx, &confirmed), nil)
+
+	reverted := confirmed
+	reverted.StatusHistory = append(append([]domain.StatusChange(nil), confirmed.StatusHistory...),
+		domain.StatusChange{From: domain.StatusConfirmed, To: domain.StatusPending, At: base})
+	reverted.Status = domain.StatusPending
+	c.expectErr("update confirmed to pending", repo.Update(ctx, &reverted), domain.ErrInvalidTransition)
+
+	unrecorded := confirmed
+	unrecorded.Status = domain.StatusCancelled
+	c.expectErr("update status without history", repo.Update(ctx, &unrecorded), repository.ErrInvalidInput)
+
+	// Moving into another appointment's time conflicts and leaves it untouched
+	moved := *adjacent
+	moved.DateTime, moved.EndTime = at(20), at(70)
+	c.expectErr("update into overlap", repo.Update(ctx, &moved), repository.ErrConflict)
+	if got, err := repo.GetByID(ctx, adjacent.ID); err == nil && !got.DateTime.Equal(adjacent.DateTime) {
+		c.failf("update into overlap: appointment changed to %s", got.DateTime)
+	}
+
+	// Cancelling frees the slot
+	freed := confirmed
+	_ = freed.Transition(domain.StatusCancelled, domain.ActorPatient, "", base)
+	c.expectErr("update confirmed to cancelled", repo.Update(ctx, &freed), nil)
+	rebooked := newAppointment(patient, at(0), 50)
+	c.expectErr("create in freed slot", repo.Create(ctx, rebooked), nil)
+
+	unknown := newAppointment(patient, at(600), 50)
+	unknown.ID = primitive.NewObjectID()
+	c.expectErr("update unknown", repo.Update(ctx, unknown), repository.ErrNotFound)
+
+	// Listing pages by start (ties by ID), following cursors
+	other := newAppointment(primitive.NewObjectID(), at(24*60), 50)
+	other.Professional = "bruno"
+	other.CreatedAt = started.Add(-72 * time.Hour).UTC()
+	c.expectErr("create for other patient", repo.Create(ctx, other), nil)
+
+	all := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return repo.List(ctx, opts)
+	}
+	next := c.expectPage(ctx, "list first page", all, repository.ListOptions{Limit: 2}, true, first.ID, rebooked.ID)
+	next = c.expectPage(ctx, "list second page", all, repository.ListOptions{Limit: 2, Cursor: next}, true, cancelled.ID, adjacent.ID)
+	c.expectPage(ctx, "list last page", all, repository.ListOptions{Limit: 2, Cursor: next}, false, other.ID)
+	c.expectStream(ctx, "stream", all, repository.ListOptions{Limit: 2},
+		first.ID, rebooked.ID, cancelled.ID, adjacent.ID, other.ID)
+	c.expectStream(ctx, "stream descending", all, repository.ListOptions{Limit: 3, Desc: true},
+		other.ID, adjacent.ID, cancelled.ID, rebooked.ID, first.ID)
+	c.expectStream(ctx, "stream by id", all, repository.ListOptions{Limit: 2, Sort: repository.SortByID},
+		first.ID, adjacent.ID, cancelled.ID, rebooked.ID, other.ID)
+
+	_, err = repo.List(ctx, repository.ListOptions{Cursor: "not-a-cursor"})
+	c.expectErr("list with malformed cursor", err, repository.ErrInvalidInput)
+	_, err = repo.List(ctx, repository.ListOptions{Cursor: next, Sort: repository.SortByID})
+	c.expectErr("list with cursor of other order", err, repository.ErrInvalidInput)
+	_, err = repo.List(ctx, repository.ListOptions{Sort: "name"})
+	c.expectErr("list with unknown sort", err, repository.ErrInvalidInput)
+
+	c.expectStream(ctx, "list by patient", func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return repo.ListByPatient(ctx, other.Patient, opts)
+	}, repository.ListOptions{}, other.ID)
+	c.expectStream(ctx, "list by status", func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return repo.ListByStatus(ctx, domain.StatusCancelled, opts)
+	}, repository.ListOptions{Limit: 1}, first.ID, cancelled.ID)
+	c.expectRange(ctx, repo, "range after rebooking", at(-60), at(120), first.ID, rebooked.ID, cancelled.ID, adjacent.ID)
+
+	// Find combines filter fields; zero fields match any
+	find := func(filter repository.AppointmentFilter) repository.AppointmentLister {
+		return func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+			return repo.Find(ctx, filter, opts)
+		}
+	}
+	open := []string{domain.StatusPending, domain.StatusConfirmed}
+	c.expectStream(ctx, "find open of patient", find(repository.AppointmentFilter{Patient: patient, Statuses: open}),
+		repository.ListOptions{Limit: 1}, rebooked.ID, adjacent.ID)
+	c.expectStream(ctx, "find open of patient in range", find(repository.AppointmentFilter{Patient: patient, Statuses: open, Start: at(50), End: at(24 * 60)}),
+		repository.ListOptions{}, adjacent.ID)
+	c.expectStream(ctx, "find with open start", find(repository.AppointmentFilter{End: at(10)}),
+		repository.ListOptions{}, first.ID, rebooked.ID)
+	c.expectStream(ctx, "find with open end", find(repository.AppointmentFilter{Start: at(60)}),
+		repository.ListOptions{}, adjacent.ID, other.ID)
+	c.expectStream(ctx, "find by professional", find(repository.AppointmentFilter{Professional: "ana"}),
+		repository.ListOptions{}, adjacent.ID)
+	c.expectStream(ctx, "find pending created before", find(repository.AppointmentFilter{Statuses: open[:1], CreatedTo: started.Add(-48 * time.Hour)}),
+		repository.ListOptions{}, other.ID)
+	c.expectStream(ctx, "find created since", find(repository.AppointmentFilter{CreatedFrom: started}),
+		repository.ListOptions{Desc: true}, adjacent.ID, cancelled.ID, rebooked.ID, first.ID)
+
+	// Delete is soft: hidden from reads, frees the slot, can be restored
+	c.expectErr("delete", repo.Delete(repository.WithActor(ctx, domain.ActorStaff), adjacent.ID), nil)
+	_, err = repo.GetByID(ctx, adjacent.ID)
+	c.expectErr("get deleted", err, repository.ErrNotFound)
+	if got, err := repo.GetByID(repository.WithDeleted(ctx), adjacent.ID); err != nil {
+		c.failf("get deleted with deleted: %v", err)
+	} else if !got.IsDeleted() || got.DeletedBy != domain.ActorStaff {
+		c.failf("get deleted with deleted: got deleted at %s by %q, want by %q", got.DeletedAt, got.DeletedBy, domain.ActorStaff)
+	}
+	c.expectRange(ctx, repo, "range without deleted", at(-60), at(120), first.ID, rebooked.ID, cancelled.ID)
+	c.expectRange(repository.WithDeleted(ctx), repo, "range with deleted", at(-60), at(120),
+		first.ID, rebooked.ID, cancelled.ID, adjacent.ID)
+	c.expectErr("update deleted", repo.Update(ctx, adjacent), repository.ErrNotFound)
+	c.expectErr("delete again", repo.Delete(ctx, adjacent.ID), repository.ErrNotFound)
+
+	replacement := newAppointment(patient, at(50), 50)
+	c.expectErr("create in deleted slot", repo.Create(ctx, replacement), nil)
+	c.expectErr("restore into taken slot", repo.Restore(ctx, adjacent.ID), repository.ErrConflict)
+	c.expectErr("delete replacement", repo.Delete(ctx, replacement.ID), nil)
+	c.expectErr("restore", repo.Restore(ctx, adjacent.ID), nil)
+	if got, err := repo.GetByID(ctx, adjacent.ID); err != nil {
+		c.failf("get restored: %v", err)
+	} else if got.IsDeleted() || got.DeletedBy != "" {
+		c.failf("get restored: still deleted at %s by %q", got.DeletedAt, got.DeletedBy)
+	}
+	c.expectErr("create over restored", repo.Create(ctx, newAppointment(patient, at(60), 30)), repository.ErrConflict)
+	c.expectErr("restore live", repo.Restore(ctx, adjacent.ID), repository.ErrNotFound)
+	c.expectErr("restore unknown", repo.Restore(ctx, primitive.NewObjectID()), repository.ErrNotFound)
+
+	// Purge removes appointments deleted before cutoff
+	c.expectCount("purge before deletion", func() (int, error) { return repo.Purge(ctx, time.Now().Add(-time.Hour)) }, 0)
+	c.expectCount("purge", func() (int, error) { return repo.Purge(ctx, time.Now().Add(time.Hour)) }, 1)
+	_, err = repo.GetByID(repository.WithDeleted(ctx), replacement.ID)
+	c.expectErr("get purged", err, repository.ErrNotFound)
+
+	// Reschedule keeps both writes or neither. Failures below are rejections,
+	// not storage errors, so backends that undo instead of rolling back get
+	// to undo
+	orig := newAppointment(patient, at(48*60), 50)
+	c.expectErr("create to reschedule", repo.Create(ctx, orig), nil)
+	c.expectErr("create blocking reschedule", repo.Create(ctx, newAppointment(patient, at(49*60), 50)), nil)
+	closed := newAppointment(patient, at(47*60), 50)
+	_ = closed.Transition(domain.StatusCancelled, domain.ActorStaff, "", base)
+	c.expectErr("create cancelled to reschedule", repo.Create(ctx, closed), nil)
+
+	reschedule := func(from *domain.Appointment, start time.Time) (*domain.Appointment, *domain.Appointment) {
+		marked := *from
+		marked.StatusHistory = append(append([]domain.StatusChange(nil), from.StatusHistory...),
+			domain.StatusChange{From: from.Status, To: domain.StatusRescheduled, At: base})
+		marked.Status = domain.StatusRescheduled
+		successor := newAppointment(patient, start, 50)
+		successor.ID = primitive.NewObjectID()
+		successor.RescheduledFrom = from.ID
+		marked.RescheduledTo = successor.ID
+		return &marked, successor
+	}
+	expectUnchanged := func(step string, from, successor *domain.Appointment) {
+		if got, err := repo.GetByID(ctx, from.ID); err != nil {
+			c.failf("%s: get original: %v", step, err)
+		} else if got.Status != from.Status || !got.RescheduledTo.IsZero() {
+			c.failf("%s: original changed to %s, rescheduled to %s", step, got.Status, got.RescheduledTo.Hex())
+		}
+		_, err := repo.GetByID(repository.WithDeleted(ctx), successor.ID)
+		c.expectErr(step+": new appointment kept", err, repository.ErrNotFound)
+	}
+
+	marked, successor := reschedule(orig, at(49*60+30))
+	c.expectErr("reschedule into overlap", repo.Reschedule(ctx, marked, successor), repository.ErrConflict)
+	expectUnchanged("reschedule into overlap", orig, successor)
+
+	marked, successor = reschedule(orig, at(48*60+30))
+	c.expectErr("reschedule into own slot", repo.Reschedule(ctx, marked, successor), repository.ErrConflict)
+	expectUnchanged("reschedule into own slot", orig, successor)
+
+	// New appointment is written first, so this exercises undoing it
+	marked, successor = reschedule(closed, at(50*60))
+	c.expectErr("reschedule cancelled", repo.Reschedule(ctx, marked, successor), domain.ErrInvalidTransition)
+	expectUnchanged("reschedule cancelled", closed, successor)
+
+	marked, successor = reschedule(newAppointment(patient, at(46*60), 50), at(50*60))
+	marked.ID = primitive.NewObjectID()
+	c.expectErr("reschedule unknown", repo.Reschedule(ctx, marked, successor), repository.ErrNotFound)
+	_, err = repo.GetByID(repository.WithDeleted(ctx), successor.ID)
+	c.expectErr("reschedule unknown: new appointment kept", err, repository.ErrNotFound)
+
+	marked, successor = reschedule(orig, at(50*60))
+	c.expectErr("reschedule", repo.Reschedule(ctx, marked, successor), nil)
+	if got, err := repo.GetByID(ctx, orig.ID); err != nil {
+		c.failf("get rescheduled original: %v", err)
+	} else if got.Status != domain.StatusRescheduled || got.RescheduledTo != successor.ID {
+		c.failf("get rescheduled original: got %s to %s, want %s to %s",
+			got.Status, got.RescheduledTo.Hex(), domain.StatusRescheduled, successor.ID.Hex())
+	}
+	if got, err := repo.GetByID(ctx, successor.ID); err != nil {
+		c.failf("get rescheduled: %v", err)
+	} else if got.Status != domain.StatusPending || got.RescheduledFrom != orig.ID || !got.DateTime.Equal(at(50*60)) {
+		c.failf("get rescheduled: got %s at %s from %s", got.Status, got.DateTime, got.RescheduledFrom.Hex())
+	}
+	c.expectErr("create in slot freed by reschedule", repo.Create(ctx, newAppointment(patient, at(48*60), 50)), nil)
+
+	// Concurrent bookings of overlapping slots: exactly one is kept
+	const racers = 8
+	results := make([]error, racers)
+	var wg sync.WaitGroup
+	for i := range racers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = repo.Create(ctx, newAppointment(primitive.NewObjectID(), at(72*60+i*5), 50))
+		}()
+	}
+	wg.Wait()
+	var booked int
+	for i, err := range results {
+		switch {
+		case err == nil:
+			booked++
+		case !errors.Is(err, repository.ErrConflict):
+			c.failf("concurrent create %d: got %v, want nil or %v", i, err, repository.ErrConflict)
+		}
+	}
+	if booked != 1 {
+		c.failf("concurrent create: %d of %d succeeded, want 1", booked, racers)
+	}
+	c.expectCount("concurrent create: stored", func() (int, error) {
+		page, err := repo.ListByDateRange(ctx, at(72*60), at(74*60), repository.ListOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return len(page.Appointments), nil
+	}, 1)
+
+	return c.err()
+}
+
+// newAppointment builds pending appointment lasting minutes.
+func newAppointment(patient primitive.ObjectID, start time.Time, minutes int) *domain.Appointment {
+	apt := &domain.Appointment{
+		DateTime: start,
+		EndTime:  start.Add(time.Duration(minutes) * time.Minute),
+		Type:     domain.DefaultAppointmentType,
+		Patient:  patient,
+	}
+	_ = apt.Transition(domain.StatusPending, domain.ActorStaff, "", start.Add(-24*time.Hour))
+	return apt
+}
+
+// expectRange records mismatch unless range lists exactly want, in order.
+// Pages hold one appointment each to exercise cursors.
+func (c *checker) expectRange(ctx context.Context, repo repository.AppointmentRepository, step string, start, end time.Time, want ...primitive.ObjectID) {
+	c.expectStream(ctx, step, func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return repo.ListByDateRange(ctx, start, end, opts)
+	}, repository.ListOptions{Limit: 1}, want...)
+}
+
+// expectStream records mismatch unless streaming list yields exactly want, in order.
+func (c *checker) expectStream(ctx context.Context, step string, list repository.AppointmentLister, opts repository.ListOptions, want ...primitive.ObjectID) {
+	var got []*domain.Appointment
+	for apt, err := range repository.StreamAppointments(ctx, opts, list) {
+		if err != nil {
+			c.failf("%s: %v", step, err)
+			return
+		}
+		got = append(got, apt)
+	}
+	c.expectIDs(step, got, want)
+}
+
+// expectPage records mismatch unless one page lists exactly want and has a
+// successor cursor if hasNext. Returns the successor cursor.
+func (c *checker) expectPage(ctx context.Context, step string, list repository.AppointmentLister, opts repository.ListOptions, hasNext bool, want ...primitive.ObjectID) string {
+	page, err := list(ctx, opts)
+	if err != nil {
+		c.failf("%s: %v", step, err)
+		return ""
+	}
+	if (page.Next != "") != hasNext {
+		c.failf("%s: got successor cursor %q, want one: %v", step, page.Next, hasNext)
+	}
+	c.expectIDs(step, page.Appointments, want)
+	return page.Next
+}
+
+// expectIDs records mismatch unless got has exactly want IDs, in order.
+func (c *checker) expectIDs(step string, got []*domain.Appointment, want []primitive.ObjectID) {
+	ok := len(got) == len(want)
+	for i := 0; ok && i < len(got); i++ {
+		ok = got[i].ID == want[i]
+	}
+	if !ok {
+		ids := make([]string, len(got))
+		for i, apt := range got {
+			ids[i] = apt.ID.Hex()
+		}
+		wantIDs := make([]string, len(want))
+		for i, id := range want {
+			wantIDs[i] = id.Hex()
+		}
+		c.failf("%s: got %v, want %v", step, ids, wantIDs)
+	}
+}
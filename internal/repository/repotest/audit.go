@@ -0,0 +1,75 @@
+package repotest
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit checks AuditRepository semantics on an empty repository.
+func Audit(ctx context.Context, repo repository.AuditRepository) error {
+	c := &checker{name: "AuditRepository"}
+
+	at := time.Date(2030, 3, 4, 10, 0, 0, 0, time.UTC)
+	patientID := primitive.NewObjectID()
+
+	c.expectErr("append without actor", repo.Append(ctx, &domain.AuditEntry{
+		At: at, Action: domain.AuditCreate, Entity: domain.EntityPatient, EntityID: patientID,
+	}), repository.ErrInvalidInput)
+	c.expectErr("append without entity ID", repo.Append(ctx, &domain.AuditEntry{
+		At: at, Actor: domain.ActorStaff, Action: domain.AuditDelete, Entity: domain.EntityPatient,
+	}), repository.ErrInvalidInput)
+
+	// Appended out of time order: listing sorts by time
+	update := &domain.AuditEntry{
+		At: at.Add(time.Minute), Actor: domain.ActorStaff, Action: domain.AuditUpdate,
+		Entity: domain.EntityPatient, EntityID: patientID,
+		Changes: []domain.FieldChange{{Field: "name", Before: `"Ana"`, After: `"Ana Souza"`}},
+	}
+	create := &domain.AuditEntry{
+		At: at, Actor: domain.ActorPatient, CorrelationID: "MSG1", Action: domain.AuditCreate,
+		Entity: domain.EntityPatient, EntityID: patientID,
+		Changes: []domain.FieldChange{{Field: "name", After: `"Ana"`}},
+	}
+	for _, entry := range []*domain.AuditEntry{update, create} {
+		c.expectErr("append "+entry.Action, repo.Append(ctx, entry), nil)
+		if entry.ID.IsZero() {
+			c.failf("append %s: ID not assigned", entry.Action)
+		}
+	}
+
+	// Same ID on another entity, and a purge without ID, stay out of the patient's history
+	c.expectErr("append other entity", repo.Append(ctx, &domain.AuditEntry{
+		At: at, Actor: domain.ActorStaff, Action: domain.AuditCreate,
+		Entity: domain.EntityAppointment, EntityID: patientID,
+	}), nil)
+	c.expectErr("append purge", repo.Append(ctx, &domain.AuditEntry{
+		At: at, Actor: domain.ActorSystem, Action: domain.AuditPurge,
+		Entity: domain.EntityPatient, Note: "1 patients deleted before 2030-03-01T00:00:00Z",
+	}), nil)
+
+	got, err := repo.ListByEntity(ctx, domain.EntityPatient, patientID)
+	if err != nil {
+		c.failf("list by entity: %v", err)
+		return c.err()
+	}
+	want := []*domain.AuditEntry{create, update}
+	if len(got) != len(want) {
+		c.failf("list by entity: got %d entries, want %d", len(got), len(want))
+		return c.err()
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.ID != w.ID || !g.At.Equal(w.At) || g.Actor != w.Actor || g.CorrelationID != w.CorrelationID ||
+			g.Action != w.Action || g.Entity != w.Entity || g.EntityID != w.EntityID ||
+			g.Note != w.Note || !slices.Equal(g.Changes, w.Changes) {
+			c.failf("list by entity[%d]: got %+v, want %+v", i, *g, *w)
+		}
+	}
+
+	return c.err()
+}
@@ -0,0 +1,217 @@
+package repotest
+
+import (
+	"context"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Patients checks PatientRepository semantics on empty repositories,
+// including soft delete, restore, purge and erasure.
+// appointments must be the store repo's Delete checks and cascades to.
+func Patients(ctx context.Context, repo repository.PatientRepository, appointments repository.AppointmentRepository) error {
+	c := &checker{name: "PatientRepository"}
+
+	c.expectErr("create without name",
+		repo.Create(ctx, &domain.Patient{Name: " ", Phone: "+5511999990001"}), repository.ErrInvalidInput)
+	c.expectErr("create with invalid phone",
+		repo.Create(ctx, &domain.Patient{Name: "Ana", Phone: "abc"}), repository.ErrInvalidInput)
+
+	ana := &domain.Patient{Name: "Ana Souza", Phone: "+5511999990001"}
+	c.expectErr("create", repo.Create(ctx, ana), nil)
+	if ana.ID.IsZero() {
+		c.failf("create: ID not assigned")
+	}
+
+	c.expectErr("create with duplicate phone",
+		repo.Create(ctx, &domain.Patient{Name: "Outra Ana", Phone: ana.Phone}), repository.ErrDuplicate)
+
+	if got, err := repo.GetByID(ctx, ana.ID); err != nil {
+		c.failf("get by id: %v", err)
+	} else if *got != *ana {
+		c.failf("get by id: got %+v, want %+v", *got, *ana)
+	}
+
+	if got, err := repo.GetByPhone(ctx, ana.Phone); err != nil {
+		c.failf("get by phone: %v", err)
+	} else if got.ID != ana.ID {
+		c.failf("get by phone: got %s, want %s", got.ID.Hex(), ana.ID.Hex())
+	}
+
+	_, err := repo.GetByID(ctx, primitive.NewObjectID())
+	c.expectErr("get unknown id", err, repository.ErrNotFound)
+	_, err = repo.GetByPhone(ctx, "+5511000000000")
+	c.expectErr("get unknown phone", err, repository.ErrNotFound)
+
+	// Returned values must not alias stored ones
+	if got, err := repo.GetByID(ctx, ana.ID); err == nil {
+		got.Name = "Changed"
+		if again, err := repo.GetByID(ctx, ana.ID); err == nil && again.Name != ana.Name {
+			c.failf("get by id: returned patient aliases stored one")
+		}
+	}
+
+	bruno := &domain.Patient{Name: "Bruno Lima", Phone: "+5511999990002"}
+	c.expectErr("create second", repo.Create(ctx, bruno), nil)
+
+	ana.Name = "Ana Souza Lima"
+	c.expectErr("update", repo.Update(ctx, ana), nil)
+	if got, err := repo.GetByID(ctx, ana.ID); err == nil && got.Name != ana.Name {
+		c.failf("update: name not persisted, got %q", got.Name)
+	}
+
+	taken := *bruno
+	taken.Phone = ana.Phone
+	c.expectErr("update to taken phone", repo.Update(ctx, &taken), repository.ErrDuplicate)
+
+	invalid := *bruno
+	invalid.Name = ""
+	c.expectErr("update invalid", repo.Update(ctx, &invalid), repository.ErrInvalidInput)
+
+	unknown := &domain.Patient{ID: primitive.NewObjectID(), Name: "Ninguém", Phone: "+5511999990003"}
+	c.expectErr("update unknown", repo.Update(ctx, unknown), repository.ErrNotFound)
+
+	// Listing and search order by name, ignoring case and accents
+	joao := &domain.Patient{Name: "João da Silva", Phone: "+5511999990004"}
+	c.expectErr("create accented", repo.Create(ctx, joao), nil)
+	maria := &domain.Patient{Name: "maria Joaquina", Phone: "+5511999990005"}
+	c.expectErr("create lowercase", repo.Create(ctx, maria), nil)
+
+	list := func(page repository.Page) func() ([]*domain.Patient, error) {
+		return func() ([]*domain.Patient, error) { return repo.List(ctx, page) }
+	}
+	search := func(query string) func() ([]*domain.Patient, error) {
+		return func() ([]*domain.Patient, error) { return repo.Search(ctx, query, repository.Page{}) }
+	}
+	c.expectPatients("list", list(repository.Page{}), ana, bruno, joao, maria)
+	c.expectPatients("list page", list(repository.Page{Offset: 1, Limit: 2}), bruno, joao)
+	c.expectPatients("list past end", list(repository.Page{Offset: 10}))
+	c.expectPatients("search unaccented", search("joao"), joao)
+	c.expectPatients("search uppercase accented", search("JOÃO"), joao)
+	c.expectPatients("search word prefix", search("jo"), joao, maria)
+	c.expectPatients("search several terms", search("sil  da"), joao)
+	c.expectPatients("search inside word", search("ima"))
+	c.expectPatients("search regex characters", search("a.*"))
+	c.expectPatients("search empty", search(" "), ana, bruno, joao, maria)
+	c.expectPatients("search page", func() ([]*domain.Patient, error) {
+		return repo.Search(ctx, "lima", repository.Page{Offset: 1})
+	}, bruno)
+
+	// Delete refuses while upcoming appointments exist, unless cascading
+	upcoming := &domain.Appointment{
+		DateTime: time.Now().Add(48 * time.Hour).Truncate(time.Hour),
+		Type:     domain.DefaultAppointmentType,
+		Patient:  joao.ID,
+	}
+	upcoming.EndTime = upcoming.DateTime.Add(50 * time.Minute)
+	_ = upcoming.Transition(domain.StatusConfirmed, domain.ActorStaff, "", time.Now())
+	c.expectErr("create upcoming appointment", appointments.Create(ctx, upcoming), nil)
+
+	past := *upcoming
+	past.ID = primitive.NilObjectID
+	past.Patient = maria.ID
+	past.DateTime, past.EndTime = past.DateTime.Add(-96*time.Hour), past.EndTime.Add(-96*time.Hour)
+	c.expectErr("create past appointment", appointments.Create(ctx, &past), nil)
+
+	c.expectErr("delete with upcoming appointment", repo.Delete(ctx, joao.ID, false), repository.ErrHasDependents)
+	_, err = repo.GetByID(ctx, joao.ID)
+	c.expectErr("get refused delete", err, nil)
+
+	staff := repository.WithActor(ctx, domain.ActorStaff)
+	c.expectErr("delete cascading", repo.Delete(staff, joao.ID, true), nil)
+	_, err = repo.GetByID(ctx, joao.ID)
+	c.expectErr("get deleted", err, repository.ErrNotFound)
+	_, err = repo.GetByPhone(ctx, joao.Phone)
+	c.expectErr("get deleted by phone", err, repository.ErrNotFound)
+	_, err = appointments.GetByID(ctx, upcoming.ID)
+	c.expectErr("get cascaded appointment", err, repository.ErrNotFound)
+
+	c.expectErr("delete with past appointment", repo.Delete(ctx, maria.ID, false), nil)
+	_, err = appointments.GetByID(ctx, past.ID)
+	c.expectErr("get past appointment of deleted", err, repository.ErrNotFound)
+
+	// Deleted patients are kept, readable on request and attributed to the actor
+	if got, err := repo.GetByID(repository.WithDeleted(ctx), joao.ID); err != nil {
+		c.failf("get deleted with deleted: %v", err)
+	} else if !got.IsDeleted() || got.DeletedBy != domain.ActorStaff {
+		c.failf("get deleted with deleted: got deleted at %s by %q, want by %q", got.DeletedAt, got.DeletedBy, domain.ActorStaff)
+	}
+	c.expectPatients("list without deleted", list(repository.Page{}), ana, bruno)
+	c.expectPatients("list with deleted", func() ([]*domain.Patient, error) {
+		return repo.List(repository.WithDeleted(ctx), repository.Page{})
+	}, ana, bruno, joao, maria)
+	c.expectPatients("search without deleted", search("jo"))
+
+	c.expectErr("update deleted", repo.Update(ctx, joao), repository.ErrNotFound)
+	c.expectErr("delete again", repo.Delete(ctx, joao.ID, false), repository.ErrNotFound)
+	c.expectErr("create with phone of deleted",
+		repo.Create(ctx, &domain.Patient{Name: "João Souza", Phone: joao.Phone}), repository.ErrDuplicate)
+
+	// Restore brings back the patient with appointments deleted alongside
+	c.expectErr("restore", repo.Restore(ctx, joao.ID), nil)
+	if got, err := repo.GetByID(ctx, joao.ID); err != nil {
+		c.failf("get restored: %v", err)
+	} else if got.IsDeleted() || got.DeletedBy != "" {
+		c.failf("get restored: still deleted at %s by %q", got.DeletedAt, got.DeletedBy)
+	}
+	_, err = appointments.GetByID(ctx, upcoming.ID)
+	c.expectErr("get restored appointment", err, nil)
+	c.expectErr("restore live", repo.Restore(ctx, joao.ID), repository.ErrNotFound)
+	c.expectErr("restore unknown", repo.Restore(ctx, primitive.NewObjectID()), repository.ErrNotFound)
+
+	// Purge removes patients deleted before cutoff with their appointments
+	c.expectCount("purge before deletion", func() (int, error) { return repo.Purge(ctx, time.Now().Add(-time.Hour)) }, 0)
+	c.expectCount("purge", func() (int, error) { return repo.Purge(ctx, time.Now().Add(time.Hour)) }, 1)
+	_, err = repo.GetByID(repository.WithDeleted(ctx), maria.ID)
+	c.expectErr("get purged", err, repository.ErrNotFound)
+	_, err = appointments.GetByID(repository.WithDeleted(ctx), past.ID)
+	c.expectErr("get appointment of purged", err, repository.ErrNotFound)
+	c.expectErr("create with phone of purged",
+		repo.Create(ctx, &domain.Patient{Name: "Maria Souza", Phone: maria.Phone}), nil)
+
+	// Erase anonymizes patient but keeps appointments for statistics
+	c.expectErr("erase", repo.Erase(ctx, joao.ID), nil)
+	if got, err := repo.GetByID(ctx, joao.ID); err != nil {
+		c.failf("get erased: %v", err)
+	} else if !got.IsErased() || got.Name != "" || got.Phone == joao.Phone {
+		c.failf("get erased: got %+v, want no name and phone", *got)
+	}
+	_, err = repo.GetByPhone(ctx, joao.Phone)
+	c.expectErr("get erased by phone", err, repository.ErrNotFound)
+	c.expectPatients("search erased", search("jo"))
+	_, err = appointments.GetByID(ctx, upcoming.ID)
+	c.expectErr("get appointment of erased", err, nil)
+	c.expectErr("create with phone of erased",
+		repo.Create(ctx, &domain.Patient{Name: "João Pereira", Phone: joao.Phone}), nil)
+	c.expectErr("erase unknown", repo.Erase(ctx, primitive.NewObjectID()), repository.ErrNotFound)
+
+	return c.err()
+}
+
+// expectPatients records mismatch unless list returns exactly want, in order.
+func (c *checker) expectPatients(step string, list func() ([]*domain.Patient, error), want ...*domain.Patient) {
+	got, err := list()
+	if err != nil {
+		c.failf("%s: %v", step, err)
+		return
+	}
+
+	ok := len(got) == len(want)
+	for i := 0; ok && i < len(got); i++ {
+		ok = got[i].ID == want[i].ID
+	}
+	if !ok {
+		names := make([]string, len(got))
+		for i, p := range got {
+			names[i] = p.Name
+		}
+		wantNames := make([]string, len(want))
+		for i, p := range want {
+			wantNames[i] = p.Name
+		}
+		c.failf("%s: got %q, want %q", step, names, wantNames)
+	}
+}
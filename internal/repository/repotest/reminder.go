@@ -0,0 +1,92 @@
+package repotest
+
+import (
+	"context"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reminders checks ReminderRepository semantics on an empty repository:
+// validation, Create as a per appointment, start and offset lock, sending,
+// lookups by message and latest sent per patient, and not-found errors.
+func Reminders(ctx context.Context, repo repository.ReminderRepository) error {
+	c := &checker{name: "ReminderRepository"}
+
+	patient := primitive.NewObjectID()
+	start := time.Date(2030, 2, 4, 14, 0, 0, 0, time.UTC)
+	reminder := func(appointment primitive.ObjectID, at time.Time, offset time.Duration) *domain.Reminder {
+		return &domain.Reminder{Appointment: appointment, Patient: patient, AppointmentAt: at, Offset: offset}
+	}
+
+	c.expectErr("create without appointment",
+		repo.Create(ctx, reminder(primitive.NilObjectID, start, 24*time.Hour)), repository.ErrInvalidInput)
+
+	first := primitive.NewObjectID()
+	day := reminder(first, start, 24*time.Hour)
+	c.expectErr("create", repo.Create(ctx, day), nil)
+	if day.ID.IsZero() {
+		c.failf("create: ID not assigned")
+	}
+	c.expectErr("create same offset", repo.Create(ctx, reminder(first, start, 24*time.Hour)), repository.ErrDuplicate)
+	hours := reminder(first, start, 2*time.Hour)
+	c.expectErr("create other offset", repo.Create(ctx, hours), nil)
+	// Moved appointment is reminded again
+	moved := reminder(first, start.Add(time.Hour), 24*time.Hour)
+	c.expectErr("create for new start", repo.Create(ctx, moved), nil)
+
+	// Released lock can be taken again
+	c.expectErr("delete", repo.Delete(ctx, moved.ID), nil)
+	c.expectErr("delete again", repo.Delete(ctx, moved.ID), repository.ErrNotFound)
+	c.expectErr("create after delete", repo.Create(ctx, reminder(first, start.Add(time.Hour), 24*time.Hour)), nil)
+
+	list, err := repo.ListByAppointment(ctx, first)
+	if err != nil {
+		c.failf("list by appointment: %v", err)
+	} else if len(list) != 3 {
+		c.failf("list by appointment: got %d reminders, want 3", len(list))
+	}
+	if list, err := repo.ListByAppointment(ctx, primitive.NewObjectID()); err != nil || len(list) != 0 {
+		c.failf("list by unknown appointment: got %d reminders, error %v", len(list), err)
+	}
+
+	// Unsent reminders are not found by message or as latest
+	_, err = repo.LatestByPatient(ctx, patient, start.Add(-48*time.Hour))
+	c.expectErr("latest before sending", err, repository.ErrNotFound)
+
+	sentAt := start.Add(-24 * time.Hour)
+	c.expectErr("mark sent", repo.MarkSent(ctx, day.ID, "MSG-DAY", sentAt), nil)
+	c.expectErr("mark unknown sent", repo.MarkSent(ctx, primitive.NewObjectID(), "MSG-X", sentAt), repository.ErrNotFound)
+	if got, err := repo.GetByMessageID(ctx, "MSG-DAY"); err != nil {
+		c.failf("get by message: %v", err)
+	} else if got.ID != day.ID || got.Offset != day.Offset || !got.AppointmentAt.Equal(start) || !got.SentAt.Equal(sentAt) {
+		c.failf("get by message: got %s offset %s at %s sent %s", got.ID.Hex(), got.Offset, got.AppointmentAt, got.SentAt)
+	}
+	_, err = repo.GetByMessageID(ctx, "MSG-X")
+	c.expectErr("get by unknown message", err, repository.ErrNotFound)
+
+	// Latest sent wins, for appointments still ahead
+	c.expectErr("mark second sent", repo.MarkSent(ctx, hours.ID, "MSG-HOURS", start.Add(-2*time.Hour)), nil)
+	other := reminder(primitive.NewObjectID(), start.Add(-72*time.Hour), 2*time.Hour)
+	c.expectErr("create for earlier appointment", repo.Create(ctx, other), nil)
+	c.expectErr("mark earlier sent", repo.MarkSent(ctx, other.ID, "MSG-EARLIER", start.Add(-74*time.Hour)), nil)
+
+	expectLatest := func(step string, after time.Time, want primitive.ObjectID) {
+		got, err := repo.LatestByPatient(ctx, patient, after)
+		if err != nil {
+			c.failf("%s: %v", step, err)
+		} else if got.ID != want {
+			c.failf("%s: got %s, want %s", step, got.ID.Hex(), want.Hex())
+		}
+	}
+	expectLatest("latest", start.Add(-80*time.Hour), hours.ID)
+	expectLatest("latest for later appointment", start.Add(-time.Minute), hours.ID)
+	_, err = repo.LatestByPatient(ctx, patient, start)
+	c.expectErr("latest after appointments", err, repository.ErrNotFound)
+	_, err = repo.LatestByPatient(ctx, primitive.NewObjectID(), start.Add(-80*time.Hour))
+	c.expectErr("latest of other patient", err, repository.ErrNotFound)
+
+	return c.err()
+}
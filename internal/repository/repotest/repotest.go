@@ -0,0 +1,57 @@
+// Package repotest checks repository implementations against the behaviour
+// of the MongoDB ones, so every backend can be verified with the same cases.
+//
+// Checks need an empty repository and return an error describing every
+// mismatch, or nil. From a test:
+//
+//	appointments := memory.NewAppointmentRepository()
+//	if err := repotest.Patients(ctx, memory.NewPatientRepository(appointments), appointments); err != nil {
+//		t.Fatal(err)
+//	}
+package repotest
+
+import (
+	"errors"
+	"fmt"
+)
+
+// checker collects mismatches of one conformance run.
+type checker struct {
+	name string
+	errs []error
+}
+
+// failf records mismatch.
+func (c *checker) failf(format string, args ...any) {
+	c.errs = append(c.errs, fmt.Errorf(format, args...))
+}
+
+// expectErr records mismatch unless err matches want (nil means success).
+func (c *checker) expectErr(step string, err, want error) {
+	switch {
+	case want == nil && err != nil:
+		c.failf("%s: unexpected error: %v", step, err)
+	case want != nil && !errors.Is(err, want):
+		c.failf("%s: got error %v, want %v", step, err, want)
+	}
+}
+
+// err returns all mismatches, prefixed with suite name.
+func (c *checker) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s conformance: %w", c.name, errors.Join(c.errs...))
+}
+
+// expectCount records mismatch unless count returns want.
+func (c *checker) expectCount(step string, count func() (int, error), want int) {
+	got, err := count()
+	if err != nil {
+		c.failf("%s: %v", step, err)
+		return
+	}
+	if got != want {
+		c.failf("%s: got %d, want %d", step, got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+)
+
+// Sessions checks SessionRepository semantics on an empty repository built
+// with ttl: validation, round trip, upsert of an existing sender, deletion,
+// and expiry after ttl without activity. The suite sleeps about 2*ttl, so
+// pass a short one.
+func Sessions(ctx context.Context, repo repository.SessionRepository, ttl time.Duration) error {
+	c := &checker{name: "SessionRepository"}
+
+	const sender, other = "+5511988887777", "+5511977776666"
+
+	_, err := repo.Get(ctx, sender)
+	c.expectErr("get missing", err, repository.ErrNotFound)
+	c.expectErr("save without id", repo.Save(ctx, domain.NewSession(" ")), repository.ErrInvalidInput)
+
+	session := domain.NewSession(sender)
+	session.Flow, session.Step = "booking", "date"
+	session.Slots["name"] = "Maria da Silva"
+	before := time.Now()
+	c.expectErr("save", repo.Save(ctx, session), nil)
+	if session.LastActivity.Before(before) || session.ExpiresAt.Sub(session.LastActivity) != ttl {
+		c.failf("save: activity %s and expiry %s not refreshed with ttl %s", session.LastActivity, session.ExpiresAt, ttl)
+	}
+	c.expectErr("save other sender", repo.Save(ctx, domain.NewSession(other)), nil)
+
+	got, err := repo.Get(ctx, sender)
+	if err != nil {
+		c.failf("get: %v", err)
+	} else {
+		if got.Flow != "booking" || got.Step != "date" || fmt.Sprint(got.Slots) != "map[name:Maria da Silva]" {
+			c.failf("get: got flow %q step %q slots %v", got.Flow, got.Step, got.Slots)
+		}
+		// Stored times may be truncated to milliseconds
+		if got.LastActivity.Sub(session.LastActivity).Abs() >= time.Millisecond ||
+			got.ExpiresAt.Sub(session.ExpiresAt).Abs() >= time.Millisecond {
+			c.failf("get: got activity %s expiry %s, want %s and %s",
+				got.LastActivity, got.ExpiresAt, session.LastActivity, session.ExpiresAt)
+		}
+	}
+
+	// Saving an existing sender replaces its session
+	session.Reset()
+	session.Flow = "reminder"
+	c.expectErr("save existing", repo.Save(ctx, session), nil)
+	if got, err := repo.Get(ctx, sender); err != nil {
+		c.failf("get after upsert: %v", err)
+	} else if got.Flow != "reminder" || got.Step != "" || got.Slots == nil || len(got.Slots) != 0 {
+		c.failf("get after upsert: got flow %q step %q slots %v", got.Flow, got.Step, got.Slots)
+	}
+
+	c.expectErr("delete", repo.Delete(ctx, sender), nil)
+	_, err = repo.Get(ctx, sender)
+	c.expectErr("get deleted", err, repository.ErrNotFound)
+	c.expectErr("delete again", repo.Delete(ctx, sender), repository.ErrNotFound)
+	if _, err := repo.Get(ctx, other); err != nil {
+		c.failf("get other sender after delete: %v", err)
+	}
+
+	// Activity refreshes expiry; inactivity expires the session
+	c.expectErr("save before expiry", repo.Save(ctx, session), nil)
+	time.Sleep(ttl * 2 / 3)
+	c.expectErr("save to refresh", repo.Save(ctx, session), nil)
+	time.Sleep(ttl * 2 / 3)
+	if _, err := repo.Get(ctx, sender); err != nil {
+		c.failf("get after refresh: %v", err)
+	}
+	time.Sleep(ttl)
+	_, err = repo.Get(ctx, sender)
+	c.expectErr("get expired", err, repository.ErrNotFound)
+	_, err = repo.Get(ctx, other)
+	c.expectErr("get other expired", err, repository.ErrNotFound)
+
+	// Expired sender starts over
+	c.expectErr("save after expiry", repo.Save(ctx, domain.NewSession(sender)), nil)
+	if got, err := repo.Get(ctx, sender); err != nil {
+		c.failf("get after expiry: %v", err)
+	} else if got.Flow != "" {
+		c.failf("get after expiry: got flow %q, want none", got.Flow)
+	}
+
+	return c.err()
+}
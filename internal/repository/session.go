@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+)
+
+// SessionRepository defines conversation session storage.
+// Sessions expire after the configured timeout of inactivity.
+type SessionRepository interface {
+	// Get returns ErrNotFound if session is missing or expired.
+	Get(ctx context.Context, id string) (*domain.Session, error)
+	// Save upserts session and refreshes its activity and expiry.
+	Save(ctx context.Context, session *domain.Session) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,500 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// appointmentColumns are selected by every appointment query, in scanAppointment order
+const appointmentColumns = `id, patient, datetime, end_datetime, type, status,
+	rescheduled_from, rescheduled_to, status_history, professional, created_at, deleted_at, deleted_by`
+
+// AppointmentRepo implements repository.AppointmentRepository for SQLite.
+// Overlap checks and writes share one immediate transaction, so overlapping
+// non-cancelled, live appointments are rejected atomically with repository.ErrConflict.
+type AppointmentRepo struct {
+	db *sql.DB
+}
+
+// NewAppointmentRepository creates a new SQLite appointment repository
+func NewAppointmentRepository(db *sql.DB) repository.AppointmentRepository {
+	return &AppointmentRepo{db: db}
+}
+
+// Create inserts a new appointment
+func (r *AppointmentRepo) Create(ctx context.Context, apt *domain.Appointment) error {
+	if err := apt.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	var id primitive.ObjectID
+	var createdAt time.Time
+	err := withTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		id, createdAt, err = insertAppointment(ctx, tx, apt)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	apt.ID = id
+	apt.CreatedAt = createdAt
+	log.Info().Str("appointment_id", apt.ID.Hex()).Msg("appointment created successfully")
+	return nil
+}
+
+// GetByID retrieves appointment by ID
+func (r *AppointmentRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Appointment, error) {
+	where := strings.Join(visible(ctx, []string{"id = ?"}), " AND ")
+	row := r.db.QueryRowContext(ctx, `SELECT `+appointmentColumns+` FROM appointments WHERE `+where, id.Hex())
+	apt, err := scanAppointment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get appointment by id: %w", err)
+	}
+	return apt, nil
+}
+
+// List retrieves a page of all appointments
+func (r *AppointmentRepo) List(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{}, opts)
+}
+
+// Find retrieves a page of appointments matching filter
+func (r *AppointmentRepo) Find(ctx context.Context, filter repository.AppointmentFilter, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	conditions, args := findConditions(filter)
+	page, err := r.page(ctx, visible(ctx, conditions), args, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appointments: %w", err)
+	}
+	return page, nil
+}
+
+// Update updates existing appointment.
+// Status changes must be allowed transitions (returns *domain.TransitionError).
+func (r *AppointmentRepo) Update(ctx context.Context, apt *domain.Appointment) error {
+	if err := apt.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateAppointment(ctx, tx, apt)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("appointment_id", apt.ID.Hex()).Msg("appointment updated successfully")
+	return nil
+}
+
+// Reschedule creates next and updates orig in one transaction
+func (r *AppointmentRepo) Reschedule(ctx context.Context, orig, next *domain.Appointment) error {
+	if orig.Validate() != nil || next.Validate() != nil {
+		return repository.ErrInvalidInput
+	}
+
+	var id primitive.ObjectID
+	var createdAt time.Time
+	err := withTx(ctx, r.db, func(tx *sql.Tx) (err error) {
+		if id, createdAt, err = insertAppointment(ctx, tx, next); err != nil {
+			return err
+		}
+		return updateAppointment(ctx, tx, orig)
+	})
+	if err != nil {
+		return err
+	}
+
+	next.ID = id
+	next.CreatedAt = createdAt
+	log.Info().
+		Str("appointment_id", next.ID.Hex()).
+		Str("rescheduled_from", orig.ID.Hex()).
+		Msg("appointment rescheduled successfully")
+	return nil
+}
+
+// Delete soft-deletes appointment by ID
+func (r *AppointmentRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE appointments SET deleted_at = ?, deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		toMillis(time.Now()), repository.ActorFrom(ctx), id.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to delete appointment: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete appointment: %w", err)
+	} else if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	log.Info().Str("appointment_id", id.Hex()).Msg("appointment deleted successfully")
+	return nil
+}
+
+// Restore undeletes appointment by ID
+func (r *AppointmentRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, `SELECT `+appointmentColumns+` FROM appointments
+			WHERE id = ? AND deleted_at IS NOT NULL`, id.Hex())
+		apt, err := scanAppointment(row)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return repository.ErrNotFound
+			}
+			return fmt.Errorf("failed to get deleted appointment: %w", err)
+		}
+		return restoreAppointment(ctx, tx, apt)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("appointment_id", id.Hex()).Msg("appointment restored successfully")
+	return nil
+}
+
+// Purge removes appointments deleted before cutoff with their reminders
+func (r *AppointmentRepo) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	var purged int64
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM reminders WHERE appointment IN
+			(SELECT id FROM appointments WHERE deleted_at < ?)`, toMillis(cutoff))
+		if err != nil {
+			return fmt.Errorf("failed to purge appointment reminders: %w", err)
+		}
+		result, err := tx.ExecContext(ctx, `DELETE FROM appointments WHERE deleted_at < ?`, toMillis(cutoff))
+		if err != nil {
+			return fmt.Errorf("failed to purge appointments: %w", err)
+		}
+		if purged, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to purge appointments: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		log.Info().Int64("appointments", purged).Time("cutoff", cutoff).Msg("purged deleted appointments")
+	}
+	return int(purged), nil
+}
+
+// insertAppointment inserts validated apt within tx, returning its ID and
+// creation time (assigned if unset).
+func insertAppointment(ctx context.Context, tx *sql.Tx, apt *domain.Appointment) (primitive.ObjectID, time.Time, error) {
+	id := apt.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+	createdAt := apt.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = fromMillis(toMillis(time.Now()))
+	}
+
+	history, err := marshalHistory(apt.StatusHistory)
+	if err != nil {
+		return id, createdAt, err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM appointments WHERE id = ?)`, id.Hex()).Scan(&exists)
+	if err != nil {
+		return id, createdAt, fmt.Errorf("failed to check appointment id: %w", err)
+	}
+	if exists {
+		return id, createdAt, repository.ErrDuplicate
+	}
+
+	if apt.BlocksAgenda() {
+		if err := checkOverlap(ctx, tx, id, apt.DateTime, apt.End()); err != nil {
+			return id, createdAt, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO appointments (`+appointmentColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, '')`,
+		id.Hex(), apt.Patient.Hex(), toMillis(apt.DateTime), toMillis(apt.EndTime), apt.Type, apt.Status,
+		nullID(apt.RescheduledFrom), nullID(apt.RescheduledTo), history, apt.Professional, toMillis(createdAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return id, createdAt, repository.ErrDuplicate
+		}
+		return id, createdAt, fmt.Errorf("failed to create appointment: %w", err)
+	}
+	return id, createdAt, nil
+}
+
+// updateAppointment replaces stored apt with validated apt within tx.
+func updateAppointment(ctx context.Context, tx *sql.Tx, apt *domain.Appointment) error {
+	history, err := marshalHistory(apt.StatusHistory)
+	if err != nil {
+		return err
+	}
+
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM appointments WHERE id = ? AND deleted_at IS NULL`,
+		apt.ID.Hex()).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("failed to get appointment status: %w", err)
+	}
+	if current != apt.Status {
+		if err := domain.CheckTransition(current, apt.Status); err != nil {
+			return err
+		}
+	}
+
+	if apt.BlocksAgenda() {
+		if err := checkOverlap(ctx, tx, apt.ID, apt.DateTime, apt.End()); err != nil {
+			return err
+		}
+	}
+
+	// Lineage links are only ever added
+	_, err = tx.ExecContext(ctx, `UPDATE appointments SET
+		patient = ?, datetime = ?, end_datetime = ?, type = ?, status = ?, status_history = ?, professional = ?,
+		rescheduled_from = COALESCE(?, rescheduled_from),
+		rescheduled_to = COALESCE(?, rescheduled_to)
+		WHERE id = ?`,
+		apt.Patient.Hex(), toMillis(apt.DateTime), toMillis(apt.EndTime), apt.Type, apt.Status, history, apt.Professional,
+		nullID(apt.RescheduledFrom), nullID(apt.RescheduledTo), apt.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to update appointment: %w", err)
+	}
+	return nil
+}
+
+// ListByPatient retrieves a page of appointments for patient
+func (r *AppointmentRepo) ListByPatient(ctx context.Context, patientID primitive.ObjectID, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Patient: patientID}, opts)
+}
+
+// ListByDateRange retrieves a page of appointments overlapping [start, end)
+func (r *AppointmentRepo) ListByDateRange(ctx context.Context, start, end time.Time, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Start: start, End: end}, opts)
+}
+
+// ListByStatus retrieves a page of appointments by status
+func (r *AppointmentRepo) ListByStatus(ctx context.Context, status string, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	return r.Find(ctx, repository.AppointmentFilter{Statuses: []string{status}}, opts)
+}
+
+// page retrieves one page of appointments matching all conditions.
+// Fetches one extra appointment to tell whether another page follows.
+func (r *AppointmentRepo) page(ctx context.Context, conditions []string, args []any, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+	pos, err := opts.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hex IDs sort like the ObjectIDs they encode
+	dir, cmp := "ASC", ">"
+	if opts.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	order := "id " + dir
+	if opts.Sort == repository.SortByDateTime {
+		order = "datetime " + dir + ", id " + dir
+	}
+
+	if pos != nil {
+		if opts.Sort == repository.SortByDateTime {
+			conditions = append(conditions, "(datetime "+cmp+" ? OR (datetime = ? AND id "+cmp+" ?))")
+			args = append(args, toMillis(pos.DateTime), toMillis(pos.DateTime), pos.ID.Hex())
+		} else {
+			conditions = append(conditions, "id "+cmp+" ?")
+			args = append(args, pos.ID.Hex())
+		}
+	}
+	args = append(args, opts.Limit+1)
+
+	query := `SELECT ` + appointmentColumns + ` FROM appointments`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY `+order+` LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []*domain.Appointment
+	for rows.Next() {
+		apt, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, apt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return opts.NewPage(appointments), nil
+}
+
+// findConditions translates f to WHERE conditions and their arguments
+func findConditions(f repository.AppointmentFilter) ([]string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	add := func(condition string, values ...any) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+
+	if !f.Patient.IsZero() {
+		add("patient = ?", f.Patient.Hex())
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]any, len(f.Statuses))
+		for i, status := range f.Statuses {
+			statuses[i] = status
+		}
+		add("status IN (?"+strings.Repeat(", ?", len(statuses)-1)+")", statuses...)
+	}
+	if f.Professional != "" {
+		add("professional = ?", f.Professional)
+	}
+	if !f.Start.IsZero() {
+		add("end_datetime > ?", toMillis(f.Start))
+	}
+	if !f.End.IsZero() {
+		add("datetime < ?", toMillis(f.End))
+	}
+	if !f.CreatedFrom.IsZero() {
+		add("created_at >= ?", toMillis(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		add("created_at < ?", toMillis(f.CreatedTo))
+	}
+	return conditions, args
+}
+
+// restoreAppointment undeletes stored deleted apt. Returns
+// repository.ErrConflict if its slot was taken meanwhile.
+func restoreAppointment(ctx context.Context, tx *sql.Tx, apt *domain.Appointment) error {
+	if apt.BlocksAgenda() {
+		if err := checkOverlap(ctx, tx, apt.ID, apt.DateTime, apt.End()); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `UPDATE appointments SET deleted_at = NULL, deleted_by = '' WHERE id = ?`, apt.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to restore appointment: %w", err)
+	}
+	return nil
+}
+
+// checkOverlap returns repository.ErrConflict if another non-cancelled, live
+// appointment intersects [start, end).
+func checkOverlap(ctx context.Context, tx *sql.Tx, id primitive.ObjectID, start, end time.Time) error {
+	var overlaps bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM appointments
+		WHERE id != ? AND datetime < ? AND end_datetime > ? AND status NOT IN (?, ?) AND deleted_at IS NULL)`,
+		id.Hex(), toMillis(end), toMillis(start), domain.StatusCancelled, domain.StatusRescheduled).Scan(&overlaps)
+	if err != nil {
+		return fmt.Errorf("failed to check appointment overlap: %w", err)
+	}
+	if overlaps {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+// scanAppointment reads appointment from appointmentColumns
+func scanAppointment(row scanner) (*domain.Appointment, error) {
+	var (
+		apt                  domain.Appointment
+		id, patient, history string
+		start, end, created  int64
+		from, to             sql.NullString
+		deleted              sql.NullInt64
+	)
+	err := row.Scan(&id, &patient, &start, &end, &apt.Type, &apt.Status, &from, &to, &history,
+		&apt.Professional, &created, &deleted, &apt.DeletedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if apt.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("invalid appointment id %q: %w", id, err)
+	}
+	if apt.Patient, err = primitive.ObjectIDFromHex(patient); err != nil {
+		return nil, fmt.Errorf("invalid patient id %q: %w", patient, err)
+	}
+	if apt.RescheduledFrom, err = parseNullID(from); err != nil {
+		return nil, err
+	}
+	if apt.RescheduledTo, err = parseNullID(to); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(history), &apt.StatusHistory); err != nil {
+		return nil, fmt.Errorf("invalid status history: %w", err)
+	}
+	if len(apt.StatusHistory) == 0 {
+		apt.StatusHistory = nil
+	}
+
+	apt.DateTime = fromMillis(start)
+	apt.EndTime = fromMillis(end)
+	apt.CreatedAt = fromMillis(created)
+	apt.DeletedAt = fromNullMillis(deleted)
+	return &apt, nil
+}
+
+// marshalHistory encodes status history with times at stored precision
+func marshalHistory(history []domain.StatusChange) (string, error) {
+	stored := make([]domain.StatusChange, len(history))
+	for i, change := range history {
+		change.At = fromMillis(toMillis(change.At))
+		stored[i] = change
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode status history: %w", err)
+	}
+	return string(data), nil
+}
+
+// nullID converts id to stored hex, or NULL if zero
+func nullID(id primitive.ObjectID) sql.NullString {
+	if id.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: id.Hex(), Valid: true}
+}
+
+// parseNullID converts nullable stored hex, zero ID if NULL
+func parseNullID(s sql.NullString) (primitive.ObjectID, error) {
+	if !s.Valid {
+		return primitive.NilObjectID, nil
+	}
+	id, err := primitive.ObjectIDFromHex(s.String)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("invalid object id %q: %w", s.String, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// auditColumns are selected by every audit query, in scanAuditEntry order
+const auditColumns = `id, at, actor, correlation_id, action, entity, entity_id, changes, note`
+
+// AuditRepo implements repository.AuditRepository for SQLite.
+// Triggers on audit_log abort any UPDATE or DELETE.
+type AuditRepo struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new SQLite audit repository
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &AuditRepo{db: db}
+}
+
+// Append inserts audit entry
+func (r *AuditRepo) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	if err := entry.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	id := entry.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO audit_log (`+auditColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), toMillis(entry.At), entry.Actor, entry.CorrelationID, entry.Action, entry.Entity,
+		nullID(entry.EntityID), string(changes), entry.Note)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicate
+		}
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+// ListByEntity retrieves audit entries of entity, oldest first
+func (r *AuditRepo) ListByEntity(ctx context.Context, entity string, id primitive.ObjectID) ([]*domain.AuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+auditColumns+` FROM audit_log
+		WHERE entity = ? AND entity_id = ? ORDER BY at, rowid`, entity, id.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// scanAuditEntry reads audit entry from auditColumns
+func scanAuditEntry(row scanner) (*domain.AuditEntry, error) {
+	var (
+		entry       domain.AuditEntry
+		id, changes string
+		at          int64
+		entityID    sql.NullString
+	)
+	err := row.Scan(&id, &at, &entry.Actor, &entry.CorrelationID, &entry.Action, &entry.Entity,
+		&entityID, &changes, &entry.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("invalid audit entry id %q: %w", id, err)
+	}
+	if entry.EntityID, err = parseNullID(entityID); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+		return nil, fmt.Errorf("invalid audit changes: %w", err)
+	}
+	if len(entry.Changes) == 0 {
+		entry.Changes = nil
+	}
+
+	entry.At = fromMillis(at)
+	return &entry, nil
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/repository/repotest"
+)
+
+// openTest opens an empty migrated database removed after the test.
+func openTest(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "clara.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Close(db) })
+	return db
+}
+
+func TestPatientConformance(t *testing.T) {
+	db := openTest(t)
+	if err := repotest.Patients(context.Background(), NewPatientRepository(db), NewAppointmentRepository(db)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppointmentConformance(t *testing.T) {
+	if err := repotest.Appointments(context.Background(), NewAppointmentRepository(openTest(t))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReminderConformance(t *testing.T) {
+	if err := repotest.Reminders(context.Background(), NewReminderRepository(openTest(t))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditConformance(t *testing.T) {
+	if err := repotest.Audit(context.Background(), NewAuditRepository(openTest(t))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditLogIsAppendOnly(t *testing.T) {
+	db := openTest(t)
+	if _, err := db.Exec(`INSERT INTO audit_log (id, at, actor, action, entity) VALUES ('a', 1, 'staff', 'purge', 'patient')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE audit_log SET actor = 'system'`); err == nil {
+		t.Error("update of audit entry succeeded")
+	}
+	if _, err := db.Exec(`DELETE FROM audit_log`); err == nil {
+		t.Error("delete of audit entry succeeded")
+	}
+}
+
+func TestSessionConformance(t *testing.T) {
+	ttl := 300 * time.Millisecond
+	if err := repotest.Sessions(context.Background(), NewSessionRepository(openTest(t), ttl), ttl); err != nil {
+		t.Fatal(err)
+	}
+}
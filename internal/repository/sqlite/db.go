@@ -0,0 +1,358 @@
+// Package sqlite provides SQLite repository implementations for single-clinic
+// deployments that keep all data in one file next to the WhatsApp session.
+//
+// Uniqueness guarantees match the MongoDB indexes. Times are stored as Unix
+// milliseconds (BSON datetime precision) and read back in UTC.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/mattn/go-sqlite3"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// migration changes the schema with sql, then optionally transforms data with up.
+type migration struct {
+	sql string
+	up  func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations are applied in order; index+1 is the schema version.
+// Never edit an applied migration, append a new one instead.
+var migrations = []migration{
+	// 1: initial schema, same uniqueness and indexes as the MongoDB migrations
+	{sql: `
+	CREATE TABLE patients (
+		id    TEXT PRIMARY KEY,
+		name  TEXT NOT NULL,
+		phone TEXT NOT NULL UNIQUE
+	);
+
+	CREATE TABLE appointments (
+		id               TEXT PRIMARY KEY,
+		patient          TEXT NOT NULL,
+		datetime         INTEGER NOT NULL,
+		end_datetime     INTEGER NOT NULL,
+		type             TEXT NOT NULL DEFAULT '',
+		status           TEXT NOT NULL,
+		rescheduled_from TEXT,
+		rescheduled_to   TEXT,
+		status_history   TEXT NOT NULL DEFAULT '[]'
+	);
+	CREATE INDEX appointments_patient ON appointments (patient);
+	CREATE INDEX appointments_datetime ON appointments (datetime);
+	CREATE INDEX appointments_status ON appointments (status);
+
+	CREATE TABLE reminders (
+		id             TEXT PRIMARY KEY,
+		appointment    TEXT NOT NULL,
+		patient        TEXT NOT NULL,
+		appointment_at INTEGER NOT NULL,
+		offset_ns      INTEGER NOT NULL,
+		message_id     TEXT,
+		sent_at        INTEGER,
+		UNIQUE (appointment, appointment_at, offset_ns)
+	);
+	CREATE INDEX reminders_message_id ON reminders (message_id);
+	CREATE INDEX reminders_patient_sent_at ON reminders (patient, sent_at DESC);
+
+	CREATE TABLE sessions (
+		id            TEXT PRIMARY KEY,
+		flow          TEXT NOT NULL DEFAULT '',
+		step          TEXT NOT NULL DEFAULT '',
+		slots         TEXT NOT NULL DEFAULT '{}',
+		last_activity INTEGER NOT NULL,
+		expires_at    INTEGER NOT NULL
+	);
+	CREATE INDEX sessions_expires_at ON sessions (expires_at);
+	`},
+
+	// 2: normalized patient names for search (see domain.NormalizeName)
+	{sql: `
+	ALTER TABLE patients ADD COLUMN name_normalized TEXT NOT NULL DEFAULT '';
+	CREATE INDEX patients_name_normalized ON patients (name_normalized, id);
+	`, up: backfillPatientNames},
+
+	// 3: list filters with the (datetime, id) page order
+	{sql: `
+	CREATE INDEX appointments_datetime_id ON appointments (datetime, id);
+	CREATE INDEX appointments_patient_datetime ON appointments (patient, datetime, id);
+	CREATE INDEX appointments_status_datetime ON appointments (status, datetime, id);
+	`},
+
+	// 4: appointment creation time and professional, indexed for Find
+	{sql: `
+	ALTER TABLE appointments ADD COLUMN professional TEXT NOT NULL DEFAULT '';
+	ALTER TABLE appointments ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX appointments_patient_status_datetime ON appointments (patient, status, datetime, id);
+	CREATE INDEX appointments_professional_status_datetime ON appointments (professional, status, datetime, id);
+	CREATE INDEX appointments_status_created_at ON appointments (status, created_at);
+	`, up: backfillAppointmentCreatedAt},
+
+	// 5: soft deletion; partial indexes serve the purge job
+	{sql: `
+	ALTER TABLE patients ADD COLUMN deleted_at INTEGER;
+	ALTER TABLE patients ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '';
+	ALTER TABLE appointments ADD COLUMN deleted_at INTEGER;
+	ALTER TABLE appointments ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '';
+	CREATE INDEX patients_deleted_at ON patients (deleted_at) WHERE deleted_at IS NOT NULL;
+	CREATE INDEX appointments_deleted_at ON appointments (deleted_at) WHERE deleted_at IS NOT NULL;
+	`},
+
+	// 6: append-only audit log; triggers reject changes to appended entries
+	{sql: `
+	CREATE TABLE audit_log (
+		id             TEXT PRIMARY KEY,
+		at             INTEGER NOT NULL,
+		actor          TEXT NOT NULL,
+		correlation_id TEXT NOT NULL DEFAULT '',
+		action         TEXT NOT NULL,
+		entity         TEXT NOT NULL,
+		entity_id      TEXT,
+		changes        TEXT NOT NULL DEFAULT '[]',
+		note           TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX audit_log_entity ON audit_log (entity, entity_id, at);
+	CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+	CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+	`},
+
+	// 7: erasure of patient personal data
+	{sql: `
+	ALTER TABLE patients ADD COLUMN erased_at INTEGER;
+	`},
+}
+
+// Open opens (creating if needed) the database at path and applies pending migrations.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	log.Info().Str("path", path).Msg("opening sqlite database")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+	}
+
+	// Immediate transactions take the write lock up front, so check-then-write
+	// sequences (overlap checks) cannot interleave across processes
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// SQLite allows one writer; a single connection serializes writes in-process
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping sqlite database: %w", err)
+	}
+
+	if err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Info().Str("path", path).Msg("sqlite database ready")
+	return db, nil
+}
+
+// Close closes the database.
+func Close(db *sql.DB) error {
+	if db == nil {
+		return nil
+	}
+
+	log.Info().Msg("closing sqlite database")
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("failed to close sqlite database: %w", err)
+	}
+	return nil
+}
+
+// Migrate applies migrations newer than the stored schema version, each in its own transaction.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var version int
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+
+	for v := version + 1; v <= len(migrations); v++ {
+		err := withTx(ctx, db, func(tx *sql.Tx) error {
+			m := migrations[v-1]
+			if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+				return err
+			}
+			if m.up != nil {
+				if err := m.up(ctx, tx); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+				v, toMillis(time.Now()))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", v, err)
+		}
+		log.Info().Int("version", v).Msg("applied sqlite migration")
+	}
+
+	return nil
+}
+
+// backfillPatientNames sets name_normalized of existing patients.
+func backfillPatientNames(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM patients`)
+	if err != nil {
+		return fmt.Errorf("failed to list patients: %w", err)
+	}
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan patient: %w", err)
+		}
+		names[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list patients: %w", err)
+	}
+
+	for id, name := range names {
+		_, err := tx.ExecContext(ctx, `UPDATE patients SET name_normalized = ? WHERE id = ?`, domain.NormalizeName(name), id)
+		if err != nil {
+			return fmt.Errorf("failed to backfill name_normalized: %w", err)
+		}
+	}
+	return nil
+}
+
+// backfillAppointmentCreatedAt sets created_at of existing appointments from
+// the timestamp in their ObjectID, as the MongoDB migration does.
+func backfillAppointmentCreatedAt(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM appointments WHERE created_at = 0`)
+	if err != nil {
+		return fmt.Errorf("failed to list appointments: %w", err)
+	}
+
+	var ids []primitive.ObjectID
+	for rows.Next() {
+		var hex string
+		if err := rows.Scan(&hex); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan appointment: %w", err)
+		}
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("invalid appointment id %q: %w", hex, err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list appointments: %w", err)
+	}
+
+	for _, id := range ids {
+		_, err := tx.ExecContext(ctx, `UPDATE appointments SET created_at = ? WHERE id = ?`, toMillis(id.Timestamp()), id.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to backfill created_at: %w", err)
+		}
+	}
+	return nil
+}
+
+// visible appends the live-record condition to conditions unless ctx
+// includes deleted ones (see repository.WithDeleted).
+func visible(ctx context.Context, conditions []string) []string {
+	if repository.IncludesDeleted(ctx) {
+		return conditions
+	}
+	return append(conditions, "deleted_at IS NULL")
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// withTx runs fn in a transaction, committing if it returns nil.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a UNIQUE or PRIMARY KEY constraint failure.
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
+
+// toMillis converts t to stored Unix milliseconds.
+func toMillis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// fromMillis converts stored Unix milliseconds to UTC time.
+func fromMillis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+// nullMillis converts t to stored Unix milliseconds, or NULL if zero.
+func nullMillis(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: toMillis(t), Valid: true}
+}
+
+// fromNullMillis converts nullable Unix milliseconds, zero time if NULL.
+func fromNullMillis(ms sql.NullInt64) time.Time {
+	if !ms.Valid {
+		return time.Time{}
+	}
+	return fromMillis(ms.Int64)
+}
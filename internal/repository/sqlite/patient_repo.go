@@ -0,0 +1,366 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patientColumns are selected by every patient query, in scanPatient order
+const patientColumns = `id, name, phone, deleted_at, deleted_by, erased_at`
+
+// PatientRepo implements repository.PatientRepository for SQLite
+type PatientRepo struct {
+	db *sql.DB
+}
+
+// NewPatientRepository creates a new SQLite patient repository
+func NewPatientRepository(db *sql.DB) repository.PatientRepository {
+	return &PatientRepo{db: db}
+}
+
+// Create inserts a new patient
+func (r *PatientRepo) Create(ctx context.Context, patient *domain.Patient) error {
+	if err := patient.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	id := patient.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO patients (id, name, name_normalized, phone) VALUES (?, ?, ?, ?)`,
+		id.Hex(), patient.Name, domain.NormalizeName(patient.Name), patient.Phone)
+	if err != nil {
+		// Unique phone (or ID) constraint
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicate
+		}
+		return fmt.Errorf("failed to create patient: %w", err)
+	}
+
+	patient.ID = id
+	log.Info().Str("patient_id", patient.ID.Hex()).Msg("patient created successfully")
+	return nil
+}
+
+// GetByID retrieves patient by ID
+func (r *PatientRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Patient, error) {
+	where := strings.Join(visible(ctx, []string{"id = ?"}), " AND ")
+	row := r.db.QueryRowContext(ctx, `SELECT `+patientColumns+` FROM patients WHERE `+where, id.Hex())
+	patient, err := scanPatient(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get patient by id: %w", err)
+	}
+	return patient, nil
+}
+
+// GetByPhone retrieves patient by phone
+func (r *PatientRepo) GetByPhone(ctx context.Context, phone string) (*domain.Patient, error) {
+	where := strings.Join(visible(ctx, []string{"phone = ?"}), " AND ")
+	row := r.db.QueryRowContext(ctx, `SELECT `+patientColumns+` FROM patients WHERE `+where, phone)
+	patient, err := scanPatient(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get patient by phone: %w", err)
+	}
+	return patient, nil
+}
+
+// Update updates existing patient
+func (r *PatientRepo) Update(ctx context.Context, patient *domain.Patient) error {
+	if err := patient.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE patients SET name = ?, name_normalized = ?, phone = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		patient.Name, domain.NormalizeName(patient.Name), patient.Phone, patient.ID.Hex())
+	if err != nil {
+		// Unique phone constraint
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicate
+		}
+		return fmt.Errorf("failed to update patient: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update patient: %w", err)
+	} else if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	log.Info().Str("patient_id", patient.ID.Hex()).Msg("patient updated successfully")
+	return nil
+}
+
+// List retrieves patients ordered by name
+func (r *PatientRepo) List(ctx context.Context, page repository.Page) ([]*domain.Patient, error) {
+	patients, err := r.query(ctx, nil, nil, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patients: %w", err)
+	}
+	return patients, nil
+}
+
+// Search retrieves patients whose name words start with every query term
+func (r *PatientRepo) Search(ctx context.Context, query string, page repository.Page) ([]*domain.Patient, error) {
+	terms := strings.Fields(domain.NormalizeName(query))
+	if len(terms) == 0 {
+		return r.List(ctx, page)
+	}
+
+	conditions := make([]string, len(terms))
+	var args []any
+	for i, term := range terms {
+		escaped := likeEscaper.Replace(term)
+		conditions[i] = `(name_normalized LIKE ? ESCAPE '\' OR name_normalized LIKE ? ESCAPE '\')`
+		args = append(args, escaped+"%", "% "+escaped+"%")
+	}
+
+	patients, err := r.query(ctx, conditions, args, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search patients: %w", err)
+	}
+	return patients, nil
+}
+
+// Delete soft-deletes patient and their appointments in one transaction
+func (r *PatientRepo) Delete(ctx context.Context, id primitive.ObjectID, cascade bool) error {
+	var appointments int64
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM patients WHERE id = ? AND deleted_at IS NULL)`,
+			id.Hex()).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check patient: %w", err)
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+
+		if !cascade {
+			var upcoming bool
+			err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM appointments
+				WHERE patient = ? AND status IN (?, ?) AND end_datetime > ? AND deleted_at IS NULL)`,
+				id.Hex(), domain.StatusPending, domain.StatusConfirmed, toMillis(time.Now())).Scan(&upcoming)
+			if err != nil {
+				return fmt.Errorf("failed to check upcoming appointments: %w", err)
+			}
+			if upcoming {
+				return repository.ErrHasDependents
+			}
+		}
+
+		deletedAt, deletedBy := toMillis(time.Now()), repository.ActorFrom(ctx)
+		result, err := tx.ExecContext(ctx, `UPDATE appointments SET deleted_at = ?, deleted_by = ?
+			WHERE patient = ? AND deleted_at IS NULL`, deletedAt, deletedBy, id.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to delete patient appointments: %w", err)
+		}
+		if appointments, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to delete patient appointments: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE patients SET deleted_at = ?, deleted_by = ? WHERE id = ?`,
+			deletedAt, deletedBy, id.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to delete patient: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("patient_id", id.Hex()).Int64("appointments", appointments).Msg("patient deleted successfully")
+	return nil
+}
+
+// Restore undeletes patient and their appointments deleted since in one transaction
+func (r *PatientRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	var restored int
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var deletedAt int64
+		err := tx.QueryRowContext(ctx, `SELECT deleted_at FROM patients WHERE id = ? AND deleted_at IS NOT NULL`,
+			id.Hex()).Scan(&deletedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return repository.ErrNotFound
+			}
+			return fmt.Errorf("failed to get deleted patient: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, `SELECT `+appointmentColumns+` FROM appointments
+			WHERE patient = ? AND deleted_at >= ? ORDER BY datetime, id`, id.Hex(), deletedAt)
+		if err != nil {
+			return fmt.Errorf("failed to list patient appointments: %w", err)
+		}
+		var appointments []*domain.Appointment
+		for rows.Next() {
+			apt, err := scanAppointment(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan appointment: %w", err)
+			}
+			appointments = append(appointments, apt)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to list patient appointments: %w", err)
+		}
+
+		for _, apt := range appointments {
+			err := restoreAppointment(ctx, tx, apt)
+			if errors.Is(err, repository.ErrConflict) {
+				log.Warn().Str("appointment_id", apt.ID.Hex()).Msg("appointment slot taken, left deleted")
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			restored++
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE patients SET deleted_at = NULL, deleted_by = '' WHERE id = ?`, id.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to restore patient: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("patient_id", id.Hex()).Int("appointments", restored).Msg("patient restored successfully")
+	return nil
+}
+
+// Purge removes patients deleted before cutoff with all their appointments
+// and reminders in one transaction
+func (r *PatientRepo) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	var purged int64
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		const deleted = `(SELECT id FROM patients WHERE deleted_at < ?)`
+		if _, err := tx.ExecContext(ctx, `DELETE FROM reminders WHERE patient IN `+deleted, toMillis(cutoff)); err != nil {
+			return fmt.Errorf("failed to purge patient reminders: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM appointments WHERE patient IN `+deleted, toMillis(cutoff)); err != nil {
+			return fmt.Errorf("failed to purge patient appointments: %w", err)
+		}
+		result, err := tx.ExecContext(ctx, `DELETE FROM patients WHERE deleted_at < ?`, toMillis(cutoff))
+		if err != nil {
+			return fmt.Errorf("failed to purge patients: %w", err)
+		}
+		if purged, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to purge patients: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		log.Info().Int64("patients", purged).Time("cutoff", cutoff).Msg("purged deleted patients")
+	}
+	return int(purged), nil
+}
+
+// Erase anonymizes patient and removes their reminders in one transaction
+func (r *PatientRepo) Erase(ctx context.Context, id primitive.ObjectID) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, `SELECT `+patientColumns+` FROM patients WHERE id = ?`, id.Hex())
+		patient, err := scanPatient(row)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return repository.ErrNotFound
+			}
+			return fmt.Errorf("failed to get patient: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM reminders WHERE patient = ?`, id.Hex()); err != nil {
+			return fmt.Errorf("failed to erase patient reminders: %w", err)
+		}
+
+		patient.Erase(time.Now())
+		_, err = tx.ExecContext(ctx, `UPDATE patients SET name = ?, name_normalized = '', phone = ?, erased_at = ?
+			WHERE id = ?`, patient.Name, patient.Phone, toMillis(patient.ErasedAt), id.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to erase patient: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("patient_id", id.Hex()).Msg("patient erased successfully")
+	return nil
+}
+
+// query retrieves patients visible with ctx matching all conditions, ordered by name
+func (r *PatientRepo) query(ctx context.Context, conditions []string, args []any, page repository.Page) ([]*domain.Patient, error) {
+	limit := -1 // No limit
+	if page.Limit > 0 {
+		limit = page.Limit
+	}
+	args = append(args, limit, page.Offset)
+
+	query := `SELECT ` + patientColumns + ` FROM patients`
+	if conditions = visible(ctx, conditions); len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY name_normalized, id LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patients []*domain.Patient
+	for rows.Next() {
+		patient, err := scanPatient(rows)
+		if err != nil {
+			return nil, err
+		}
+		patients = append(patients, patient)
+	}
+	return patients, rows.Err()
+}
+
+// likeEscaper escapes LIKE wildcards for ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// scanPatient reads patient from patientColumns
+func scanPatient(row scanner) (*domain.Patient, error) {
+	var (
+		patient         domain.Patient
+		id              string
+		deleted, erased sql.NullInt64
+	)
+	if err := row.Scan(&id, &patient.Name, &patient.Phone, &deleted, &patient.DeletedBy, &erased); err != nil {
+		return nil, err
+	}
+	patient.DeletedAt = fromNullMillis(deleted)
+	patient.ErasedAt = fromNullMillis(erased)
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patient id %q: %w", id, err)
+	}
+	patient.ID = oid
+	return &patient, nil
+}
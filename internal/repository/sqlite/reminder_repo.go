@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reminderColumns are selected by every reminder query, in scanReminder order
+const reminderColumns = `id, appointment, patient, appointment_at, offset_ns, message_id, sent_at`
+
+// ReminderRepo implements repository.ReminderRepository for SQLite.
+// Uniqueness relies on the (appointment, appointment_at, offset_ns) constraint.
+type ReminderRepo struct {
+	db *sql.DB
+}
+
+// NewReminderRepository creates a new SQLite reminder repository
+func NewReminderRepository(db *sql.DB) repository.ReminderRepository {
+	return &ReminderRepo{db: db}
+}
+
+// Create inserts a new reminder
+func (r *ReminderRepo) Create(ctx context.Context, reminder *domain.Reminder) error {
+	if err := reminder.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	id := reminder.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO reminders (`+reminderColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(), reminder.Appointment.Hex(), reminder.Patient.Hex(), toMillis(reminder.AppointmentAt),
+		int64(reminder.Offset), nullString(reminder.MessageID), nullMillis(reminder.SentAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrDuplicate
+		}
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	reminder.ID = id
+	log.Debug().Str("reminder_id", reminder.ID.Hex()).Msg("reminder created")
+	return nil
+}
+
+// MarkSent records delivery of reminder
+func (r *ReminderRepo) MarkSent(ctx context.Context, id primitive.ObjectID, messageID string, sentAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE reminders SET message_id = ?, sent_at = ? WHERE id = ?`,
+		messageID, toMillis(sentAt), id.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	} else if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes reminder by ID
+func (r *ReminderRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reminders WHERE id = ?`, id.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	} else if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListByAppointment retrieves reminders for appointment
+func (r *ReminderRepo) ListByAppointment(ctx context.Context, appointmentID primitive.ObjectID) ([]*domain.Reminder, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+reminderColumns+` FROM reminders
+		WHERE appointment = ? ORDER BY rowid`, appointmentID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders by appointment: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []*domain.Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode reminders by appointment: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list reminders by appointment: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// GetByMessageID retrieves reminder by sent WhatsApp message ID
+func (r *ReminderRepo) GetByMessageID(ctx context.Context, messageID string) (*domain.Reminder, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+reminderColumns+` FROM reminders WHERE message_id = ?`, messageID)
+	reminder, err := scanReminder(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get reminder by message id: %w", err)
+	}
+	return reminder, nil
+}
+
+// LatestByPatient retrieves most recently sent reminder for an upcoming appointment
+func (r *ReminderRepo) LatestByPatient(ctx context.Context, patientID primitive.ObjectID, after time.Time) (*domain.Reminder, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+reminderColumns+` FROM reminders
+		WHERE patient = ? AND appointment_at > ? AND sent_at IS NOT NULL
+		ORDER BY sent_at DESC LIMIT 1`, patientID.Hex(), toMillis(after))
+	reminder, err := scanReminder(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest reminder by patient: %w", err)
+	}
+	return reminder, nil
+}
+
+// scanReminder reads reminder from reminderColumns
+func scanReminder(row scanner) (*domain.Reminder, error) {
+	var (
+		reminder                 domain.Reminder
+		id, appointment, patient string
+		appointmentAt, offset    int64
+		messageID                sql.NullString
+		sentAt                   sql.NullInt64
+	)
+	err := row.Scan(&id, &appointment, &patient, &appointmentAt, &offset, &messageID, &sentAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if reminder.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("invalid reminder id %q: %w", id, err)
+	}
+	if reminder.Appointment, err = primitive.ObjectIDFromHex(appointment); err != nil {
+		return nil, fmt.Errorf("invalid appointment id %q: %w", appointment, err)
+	}
+	if reminder.Patient, err = primitive.ObjectIDFromHex(patient); err != nil {
+		return nil, fmt.Errorf("invalid patient id %q: %w", patient, err)
+	}
+
+	reminder.AppointmentAt = fromMillis(appointmentAt)
+	reminder.Offset = time.Duration(offset)
+	reminder.MessageID = messageID.String
+	reminder.SentAt = fromNullMillis(sentAt)
+	return &reminder, nil
+}
+
+// nullString converts s to stored text, or NULL if empty
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// SessionRepo implements repository.SessionRepository for SQLite.
+// Expired rows are purged on save, standing in for the MongoDB TTL index.
+type SessionRepo struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// NewSessionRepository creates a new SQLite session repository
+func NewSessionRepository(db *sql.DB, ttl time.Duration) repository.SessionRepository {
+	return &SessionRepo{db: db, ttl: ttl}
+}
+
+// Get retrieves non-expired session by ID
+func (r *SessionRepo) Get(ctx context.Context, id string) (*domain.Session, error) {
+	var (
+		session                domain.Session
+		slots                  string
+		lastActivity, expireAt int64
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT id, flow, step, slots, last_activity, expires_at
+		FROM sessions WHERE id = ? AND expires_at > ?`, id, toMillis(time.Now())).
+		Scan(&session.ID, &session.Flow, &session.Step, &slots, &lastActivity, &expireAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(slots), &session.Slots); err != nil {
+		return nil, fmt.Errorf("invalid session slots: %w", err)
+	}
+	if session.Slots == nil {
+		session.Slots = make(map[string]string)
+	}
+	session.LastActivity = fromMillis(lastActivity)
+	session.ExpiresAt = fromMillis(expireAt)
+	return &session, nil
+}
+
+// Save upserts session, refreshing activity and expiry
+func (r *SessionRepo) Save(ctx context.Context, session *domain.Session) error {
+	if err := session.Validate(); err != nil {
+		return repository.ErrInvalidInput
+	}
+
+	slots, err := json.Marshal(session.Slots)
+	if err != nil {
+		return fmt.Errorf("failed to encode session slots: %w", err)
+	}
+
+	now := time.Now()
+	session.LastActivity = now
+	session.ExpiresAt = now.Add(r.ttl)
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO sessions (id, flow, step, slots, last_activity, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			flow = excluded.flow, step = excluded.step, slots = excluded.slots,
+			last_activity = excluded.last_activity, expires_at = excluded.expires_at`,
+		session.ID, session.Flow, session.Step, string(slots), toMillis(session.LastActivity), toMillis(session.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	// Best effort; Get ignores expired rows anyway
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, toMillis(now)); err != nil {
+		log.Warn().Err(err).Msg("failed to purge expired sessions")
+	}
+
+	log.Debug().Str("session_id", session.ID).Str("flow", session.Flow).Msg("session saved")
+	return nil
+}
+
+// Delete removes session by ID
+func (r *SessionRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	} else if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	log.Debug().Str("session_id", id).Msg("session deleted")
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package retention purges soft-deleted records once their retention period ends.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// Purger periodically removes patients and appointments deleted longer ago
+// than the retention period.
+type Purger struct {
+	patients     repository.PatientRepository
+	appointments repository.AppointmentRepository
+	retention    time.Duration
+	interval     time.Duration
+	now          func() time.Time
+}
+
+// NewPurger creates purge job.
+// Records deleted more than retention ago are removed every interval.
+func NewPurger(
+	patients repository.PatientRepository,
+	appointments repository.AppointmentRepository,
+	retention time.Duration,
+	interval time.Duration,
+) *Purger {
+	return &Purger{
+		patients:     patients,
+		appointments: appointments,
+		retention:    retention,
+		interval:     interval,
+		now:          time.Now,
+	}
+}
+
+// Run purges every interval until ctx is cancelled.
+// Returns immediately when retention or interval is not positive.
+func (p *Purger) Run(ctx context.Context) {
+	if p.retention <= 0 || p.interval <= 0 {
+		log.Info().Msg("purge of deleted records disabled")
+		return
+	}
+
+	log.Info().
+		Dur("retention", p.retention).
+		Dur("interval", p.interval).
+		Msg("purge job started")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Tick(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("purge failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("purge job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick removes records deleted before now minus retention.
+// A failed appointment purge does not stop the patient purge.
+func (p *Purger) Tick(ctx context.Context) error {
+	cutoff := p.now().Add(-p.retention)
+
+	var errs []error
+	if _, err := p.appointments.Purge(ctx, cutoff); err != nil {
+		errs = append(errs, fmt.Errorf("failed to purge appointments: %w", err))
+	}
+	if _, err := p.patients.Purge(ctx, cutoff); err != nil {
+		errs = append(errs, fmt.Errorf("failed to purge patients: %w", err))
+	}
+	return errors.Join(errs...)
+}
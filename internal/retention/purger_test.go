@@ -0,0 +1,235 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testRetention = 30 * 24 * time.Hour
+
+var errStorage = errors.New("storage unavailable")
+
+// failingAppointments fails every purge.
+type failingAppointments struct {
+	repository.AppointmentRepository
+}
+
+func (failingAppointments) Purge(ctx context.Context, cutoff time.Time) (int, error) {
+	return 0, errStorage
+}
+
+// purgeTest holds a deleted patient, a deleted appointment of a live patient,
+// and a live appointment. The appointment is deleted first.
+type purgeTest struct {
+	t            *testing.T
+	appointments repository.AppointmentRepository
+	patients     repository.PatientRepository
+
+	deletedPatient     *domain.Patient
+	deletedAppointment *domain.Appointment
+	liveAppointment    *domain.Appointment
+}
+
+func newPurgeTest(t *testing.T) *purgeTest {
+	t.Helper()
+	ctx := context.Background()
+	appointments := memory.NewAppointmentRepository()
+	pt := &purgeTest{t: t, appointments: appointments, patients: memory.NewPatientRepository(appointments)}
+
+	pt.deletedPatient = pt.createPatient("Ana Souza", "+5511911112222")
+	live := pt.createPatient("Maria da Silva", "+5511988887777")
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	pt.deletedAppointment = pt.createAppointment(live.ID, start)
+	pt.liveAppointment = pt.createAppointment(live.ID, start.Add(time.Hour))
+
+	if err := pt.appointments.Delete(ctx, pt.deletedAppointment.ID); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond) // Deletion times are stored in milliseconds
+	if err := pt.patients.Delete(ctx, pt.deletedPatient.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	return pt
+}
+
+func (pt *purgeTest) createPatient(name, phone string) *domain.Patient {
+	pt.t.Helper()
+	patient := &domain.Patient{Name: name, Phone: phone}
+	if err := pt.patients.Create(context.Background(), patient); err != nil {
+		pt.t.Fatal(err)
+	}
+	return patient
+}
+
+func (pt *purgeTest) createAppointment(patient primitive.ObjectID, start time.Time) *domain.Appointment {
+	pt.t.Helper()
+	apt := &domain.Appointment{DateTime: start, EndTime: start.Add(50 * time.Minute), Patient: patient}
+	if err := apt.Transition(domain.StatusPending, domain.ActorPatient, "", time.Now()); err != nil {
+		pt.t.Fatal(err)
+	}
+	if err := pt.appointments.Create(context.Background(), apt); err != nil {
+		pt.t.Fatal(err)
+	}
+	return apt
+}
+
+// deletedAt returns deletion times of the deleted patient and appointment.
+func (pt *purgeTest) deletedAt() (patient, appointment time.Time) {
+	pt.t.Helper()
+	ctx := repository.WithDeleted(context.Background())
+	p, err := pt.patients.GetByID(ctx, pt.deletedPatient.ID)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+	a, err := pt.appointments.GetByID(ctx, pt.deletedAppointment.ID)
+	if err != nil {
+		pt.t.Fatal(err)
+	}
+	return p.DeletedAt, a.DeletedAt
+}
+
+// stored reports whether patient and appointment are still stored, deleted or not.
+func (pt *purgeTest) stored(patient, appointment primitive.ObjectID) (bool, bool) {
+	pt.t.Helper()
+	ctx := repository.WithDeleted(context.Background())
+	_, perr := pt.patients.GetByID(ctx, patient)
+	_, aerr := pt.appointments.GetByID(ctx, appointment)
+	for _, err := range []error{perr, aerr} {
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			pt.t.Fatal(err)
+		}
+	}
+	return perr == nil, aerr == nil
+}
+
+func (pt *purgeTest) purger(appointments repository.AppointmentRepository, now time.Time) *Purger {
+	p := NewPurger(pt.patients, appointments, testRetention, time.Hour)
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestTickCutoff(t *testing.T) {
+	pt := newPurgeTest(t)
+	patientDeleted, appointmentDeleted := pt.deletedAt()
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		patient     bool
+		appointment bool
+	}{
+		{"before retention ends", appointmentDeleted.Add(testRetention - time.Hour), true, true},
+		// Cutoff equal to deletion time keeps the record
+		{"appointment at cutoff", appointmentDeleted.Add(testRetention), true, true},
+		{"appointment past cutoff", appointmentDeleted.Add(testRetention + time.Millisecond), true, false},
+		{"patient at cutoff", patientDeleted.Add(testRetention), true, false},
+		{"patient past cutoff", patientDeleted.Add(testRetention + time.Millisecond), false, false},
+	}
+
+	if !appointmentDeleted.Before(patientDeleted) {
+		t.Fatalf("appointment deleted at %s, not before patient at %s", appointmentDeleted, patientDeleted)
+	}
+	for _, tt := range tests {
+		if err := pt.purger(pt.appointments, tt.now).Tick(context.Background()); err != nil {
+			t.Fatalf("%s: Tick: %v", tt.name, err)
+		}
+		patient, appointment := pt.stored(pt.deletedPatient.ID, pt.deletedAppointment.ID)
+		if patient != tt.patient || appointment != tt.appointment {
+			t.Errorf("%s: stored patient %v, appointment %v, want %v, %v",
+				tt.name, patient, appointment, tt.patient, tt.appointment)
+		}
+	}
+
+	// Live records are never purged
+	if _, err := pt.appointments.GetByID(context.Background(), pt.liveAppointment.ID); err != nil {
+		t.Errorf("live appointment: %v", err)
+	}
+	if _, err := pt.patients.GetByID(context.Background(), pt.liveAppointment.Patient); err != nil {
+		t.Errorf("live patient: %v", err)
+	}
+}
+
+func TestTickPurgesPatientsWhenAppointmentPurgeFails(t *testing.T) {
+	pt := newPurgeTest(t)
+	patientDeleted, _ := pt.deletedAt()
+
+	p := pt.purger(failingAppointments{pt.appointments}, patientDeleted.Add(testRetention+time.Hour))
+	err := p.Tick(context.Background())
+	if !errors.Is(err, errStorage) {
+		t.Fatalf("Tick: got %v, want %v", err, errStorage)
+	}
+
+	patient, _ := pt.stored(pt.deletedPatient.ID, primitive.NilObjectID)
+	if patient {
+		t.Error("deleted patient kept after failed appointment purge")
+	}
+	if _, appointment := pt.stored(primitive.NilObjectID, pt.deletedAppointment.ID); !appointment {
+		t.Error("deleted appointment purged by failing repository")
+	}
+}
+
+func TestRunDisabled(t *testing.T) {
+	pt := newPurgeTest(t)
+	for _, tt := range []struct{ retention, interval time.Duration }{
+		{0, time.Hour},
+		{-testRetention, time.Hour},
+		{testRetention, 0},
+		{testRetention, -time.Minute},
+	} {
+		p := NewPurger(pt.patients, pt.appointments, tt.retention, tt.interval)
+		done := make(chan struct{})
+		go func() {
+			p.Run(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Run with retention %s and interval %s did not return", tt.retention, tt.interval)
+		}
+	}
+
+	// Nothing was purged
+	if patient, appointment := pt.stored(pt.deletedPatient.ID, pt.deletedAppointment.ID); !patient || !appointment {
+		t.Errorf("disabled Run purged records: patient kept %v, appointment kept %v", patient, appointment)
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	pt := newPurgeTest(t)
+	p := pt.purger(pt.appointments, time.Now().Add(testRetention+time.Hour))
+	p.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		if patient, _ := pt.stored(pt.deletedPatient.ID, primitive.NilObjectID); !patient {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("nothing purged by Run")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/repository"
+)
+
+// Slot is a bookable time range, end exclusive.
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Overlaps reports whether slot intersects [start, end).
+func (s Slot) Overlaps(start, end time.Time) bool {
+	return s.Start.Before(end) && start.Before(s.End)
+}
+
+// Availability computes free slots from schedule and existing appointments.
+type Availability struct {
+	schedule     *Schedule
+	appointments repository.AppointmentRepository
+	now          func() time.Time
+}
+
+// NewAvailability creates availability service.
+func NewAvailability(schedule *Schedule, appointments repository.AppointmentRepository) *Availability {
+	return &Availability{
+		schedule:     schedule,
+		appointments: appointments,
+		now:          time.Now,
+	}
+}
+
+// FreeSlots returns free slots of length d starting in [from, to), ascending.
+// Slots in the past, on closures, running (plus buffer) into a break or
+// closing time, or overlapping a non-cancelled appointment (plus buffer)
+// are excluded.
+func (a *Availability) FreeSlots(ctx context.Context, from, to time.Time, d time.Duration) ([]Slot, error) {
+	loc := a.schedule.Location
+	from, to = from.In(loc), to.In(loc)
+
+	// Widen window by buffer on both sides and by the last slot's length
+	margin := a.schedule.Buffer
+	list := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return a.appointments.ListByDateRange(ctx, from.Add(-margin), to.Add(d+margin), opts)
+	}
+
+	var busy []Slot
+	for apt, err := range repository.StreamAppointments(ctx, repository.ListOptions{}, list) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list appointments: %w", err)
+		}
+		if !apt.BlocksAgenda() {
+			continue
+		}
+		busy = append(busy, Slot{
+			Start: apt.DateTime.Add(-a.schedule.Buffer),
+			End:   apt.End().Add(a.schedule.Buffer),
+		})
+	}
+
+	now := a.now()
+	var free []Slot
+	for day := midnight(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, slot := range a.schedule.DaySlots(day, d) {
+			if slot.Start.Before(from) || !slot.Start.Before(to) || slot.Start.Before(now) {
+				continue
+			}
+			if overlapsAny(slot, busy) {
+				continue
+			}
+			free = append(free, slot)
+		}
+	}
+
+	return free, nil
+}
+
+// IsFree reports whether [start, start+d) is a free slot.
+func (a *Availability) IsFree(ctx context.Context, start time.Time, d time.Duration) (bool, error) {
+	slots, err := a.FreeSlots(ctx, start, start.Add(time.Minute), d)
+	if err != nil {
+		return false, err
+	}
+	for _, slot := range slots {
+		if slot.Start.Equal(start) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Duration returns length of appointment type.
+func (a *Availability) Duration(aptType string) time.Duration {
+	return a.schedule.DurationFor(aptType)
+}
+
+// overlapsAny reports whether slot intersects any busy range.
+func overlapsAny(slot Slot, busy []Slot) bool {
+	for _, b := range busy {
+		if slot.Overlaps(b.Start, b.End) {
+			return true
+		}
+	}
+	return false
+}
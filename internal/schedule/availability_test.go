@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testAvailability returns availability for mon-fri 08:00-18:00 with lunch
+// at 12:00, 50 minute slots and a 10 minute buffer, as of the Sunday before
+// monday (a Monday).
+func testAvailability(t *testing.T) (a *Availability, appointments repository.AppointmentRepository, monday time.Time) {
+	t.Helper()
+	hours, err := ParseHours("mon-fri 08:00-18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc := time.FixedZone("BRT", -3*60*60)
+	s := &Schedule{
+		Hours:      hours,
+		Breaks:     []Interval{{Start: 12 * 60, End: 13 * 60}},
+		SlotLength: 50 * time.Minute,
+		Buffer:     10 * time.Minute,
+		Location:   loc,
+	}
+
+	monday = time.Date(2025, time.October, 20, 0, 0, 0, 0, loc)
+	appointments = memory.NewAppointmentRepository()
+	a = NewAvailability(s, appointments)
+	a.now = func() time.Time { return monday.AddDate(0, 0, -1) }
+	return a, appointments, monday
+}
+
+func book(t *testing.T, appointments repository.AppointmentRepository, start time.Time, d time.Duration, status string) {
+	t.Helper()
+	apt := &domain.Appointment{DateTime: start, EndTime: start.Add(d), Patient: primitive.NewObjectID()}
+	if err := apt.Transition(domain.StatusPending, domain.ActorStaff, "", start.AddDate(0, 0, -7)); err != nil {
+		t.Fatal(err)
+	}
+	if status != domain.StatusPending {
+		if err := apt.Transition(status, domain.ActorStaff, "", start.AddDate(0, 0, -7)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := appointments.Create(context.Background(), apt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func starts(slots []Slot) []string {
+	out := make([]string, len(slots))
+	for i, s := range slots {
+		out[i] = s.Start.Format("15:04") + "-" + s.End.Format("15:04")
+	}
+	return out
+}
+
+func TestFreeSlotsFitDuration(t *testing.T) {
+	ctx := context.Background()
+	a, _, monday := testAvailability(t)
+
+	tests := []struct {
+		duration time.Duration
+		want     []string
+	}{
+		{50 * time.Minute, []string{
+			"08:00-08:50", "09:00-09:50", "10:00-10:50", "11:00-11:50",
+			"13:00-13:50", "14:00-14:50", "15:00-15:50", "16:00-16:50", "17:00-17:50",
+		}},
+		// 11:00 runs into lunch and 17:00 past closing
+		{90 * time.Minute, []string{
+			"08:00-09:30", "09:00-10:30", "10:00-11:30",
+			"13:00-14:30", "14:00-15:30", "15:00-16:30", "16:00-17:30",
+		}},
+		// With buffer, 11:00 would end at lunch exactly
+		{60 * time.Minute, []string{
+			"08:00-09:00", "09:00-10:00", "10:00-11:00",
+			"13:00-14:00", "14:00-15:00", "15:00-16:00", "16:00-17:00",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration.String(), func(t *testing.T) {
+			slots, err := a.FreeSlots(ctx, monday, monday.AddDate(0, 0, 1), tt.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := starts(slots); !slices.Equal(got, tt.want) {
+				t.Errorf("FreeSlots(%s) = %v, want %v", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreeSlotsSkipBookedWithBuffer(t *testing.T) {
+	ctx := context.Background()
+	a, appointments, monday := testAvailability(t)
+	at := func(hour, minute int) time.Time {
+		return monday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	book(t, appointments, at(10, 0), 50*time.Minute, domain.StatusConfirmed)
+	book(t, appointments, at(15, 0), 50*time.Minute, domain.StatusCancelled)
+
+	slots, err := a.FreeSlots(ctx, monday, monday.AddDate(0, 0, 1), 90*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 09:00 would run into 10:00 and 10:00 is taken; cancelled 15:00 is free
+	want := []string{"08:00-09:30", "13:00-14:30", "14:00-15:30", "15:00-16:30", "16:00-17:30"}
+	if got := starts(slots); !slices.Equal(got, want) {
+		t.Errorf("FreeSlots = %v, want %v", got, want)
+	}
+}
+
+func TestIsFree(t *testing.T) {
+	ctx := context.Background()
+	a, appointments, monday := testAvailability(t)
+	at := func(hour, minute int) time.Time {
+		return monday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	book(t, appointments, at(10, 0), 50*time.Minute, domain.StatusPending)
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		duration time.Duration
+		want     bool
+	}{
+		{"free slot", at(9, 0), 50 * time.Minute, true},
+		{"long enough to reach next appointment", at(9, 0), 90 * time.Minute, false},
+		{"booked", at(10, 0), 30 * time.Minute, false},
+		{"right after buffer", at(11, 0), 50 * time.Minute, true},
+		{"runs into lunch", at(11, 0), 90 * time.Minute, false},
+		{"runs past closing", at(17, 0), 90 * time.Minute, false},
+		{"ends at closing without buffer", at(17, 0), 60 * time.Minute, false},
+		{"off grid", at(9, 30), 30 * time.Minute, false},
+		{"during lunch", at(12, 0), 50 * time.Minute, false},
+		{"weekend", at(0, 0).AddDate(0, 0, 5).Add(9 * time.Hour), 50 * time.Minute, false},
+		{"in the past", at(9, 0).AddDate(0, 0, -3), 50 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.IsFree(ctx, tt.start, tt.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("IsFree(%s, %s) = %v, want %v", tt.start.Format("Mon 15:04"), tt.duration, got, tt.want)
+			}
+		})
+	}
+}
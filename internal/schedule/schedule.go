@@ -0,0 +1,344 @@
+// Package schedule models clinic opening hours and computes free slots.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/domain"
+)
+
+// Interval is a time-of-day range in minutes since midnight, end exclusive.
+type Interval struct {
+	Start int
+	End   int
+}
+
+// Closure is a date range (inclusive) when the clinic is closed.
+type Closure struct {
+	From time.Time // Midnight of first closed day
+	To   time.Time // Midnight of last closed day
+}
+
+// Schedule describes when appointments can be booked.
+type Schedule struct {
+	Hours      map[time.Weekday][]Interval // Working hours per weekday
+	Breaks     []Interval                  // Daily breaks (e.g. lunch)
+	SlotLength time.Duration
+	Buffer     time.Duration            // Gap kept after each appointment
+	Durations  map[string]time.Duration // Length per appointment type
+	Closures   []Closure
+	Location   *time.Location
+}
+
+// weekdayTokens maps English and Portuguese abbreviations to weekdays.
+var weekdayTokens = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	"dom": time.Sunday, "seg": time.Monday, "ter": time.Tuesday, "qua": time.Wednesday,
+	"qui": time.Thursday, "sex": time.Friday, "sab": time.Saturday,
+}
+
+// FromConfig builds schedule from clinic configuration.
+func FromConfig(cfg *config.Config) (*Schedule, error) {
+	hours, err := ParseHours(cfg.ClinicHours)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLINIC_HOURS: %w", err)
+	}
+
+	breaks, err := parseIntervals(cfg.ClinicBreaks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLINIC_BREAKS: %w", err)
+	}
+
+	closures, err := ParseClosures(cfg.ClinicClosures, cfg.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLINIC_CLOSURES: %w", err)
+	}
+
+	if cfg.SlotMinutes <= 0 {
+		return nil, fmt.Errorf("SLOT_MINUTES must be positive")
+	}
+	if cfg.SlotBufferMinutes < 0 {
+		return nil, fmt.Errorf("SLOT_BUFFER_MINUTES cannot be negative")
+	}
+	if !onAgendaStep(cfg.SlotMinutes) || !onAgendaStep(cfg.SlotBufferMinutes) {
+		return nil, fmt.Errorf("SLOT_MINUTES and SLOT_BUFFER_MINUTES must be multiples of %s", domain.AgendaStep)
+	}
+
+	slotLength := time.Duration(cfg.SlotMinutes) * time.Minute
+	durations, err := ParseDurations(cfg.AppointmentTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APPOINTMENT_TYPES: %w", err)
+	}
+	if _, ok := durations[domain.DefaultAppointmentType]; !ok {
+		durations[domain.DefaultAppointmentType] = slotLength
+	}
+
+	return &Schedule{
+		Hours:      hours,
+		Breaks:     breaks,
+		SlotLength: slotLength,
+		Buffer:     time.Duration(cfg.SlotBufferMinutes) * time.Minute,
+		Durations:  durations,
+		Closures:   closures,
+		Location:   cfg.Location,
+	}, nil
+}
+
+// ParseHours parses weekly hours like "mon-fri 08:00-12:00,13:00-18:00; sat 08:00-12:00".
+// Day ranges wrap around the week ("sat-sun" is valid).
+func ParseHours(s string) (map[time.Weekday][]Interval, error) {
+	hours := make(map[time.Weekday][]Interval)
+
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		days, ranges, ok := strings.Cut(entry, " ")
+		if !ok {
+			return nil, fmt.Errorf("entry %q: expected \"<days> <ranges>\"", entry)
+		}
+
+		weekdays, err := parseDays(days)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry, err)
+		}
+
+		intervals, err := parseIntervals(ranges)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry, err)
+		}
+
+		for _, wd := range weekdays {
+			hours[wd] = append(hours[wd], intervals...)
+		}
+	}
+
+	return hours, nil
+}
+
+// ParseClosures parses dates "2026-12-25" and ranges "2026-12-24..2026-12-31",
+// comma-separated.
+func ParseClosures(s string, loc *time.Location) ([]Closure, error) {
+	var closures []Closure
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		fromStr, toStr, isRange := strings.Cut(item, "..")
+		if !isRange {
+			toStr = fromStr
+		}
+
+		from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(fromStr), loc)
+		if err != nil {
+			return nil, fmt.Errorf("closure %q: %w", item, err)
+		}
+		to, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(toStr), loc)
+		if err != nil {
+			return nil, fmt.Errorf("closure %q: %w", item, err)
+		}
+		if to.Before(from) {
+			return nil, fmt.Errorf("closure %q: end before start", item)
+		}
+
+		closures = append(closures, Closure{From: from, To: to})
+	}
+	return closures, nil
+}
+
+// ParseDurations parses appointment types like "retorno:30,avaliacao:60" (minutes).
+func ParseDurations(s string) (map[string]time.Duration, error) {
+	durations := make(map[string]time.Duration)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, minutesStr, ok := strings.Cut(item, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("type %q: expected \"<name>:<minutes>\"", item)
+		}
+
+		var minutes int
+		if _, err := fmt.Sscanf(strings.TrimSpace(minutesStr), "%d", &minutes); err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("type %q: minutes must be a positive number", item)
+		}
+		if !onAgendaStep(minutes) {
+			return nil, fmt.Errorf("type %q: minutes must be a multiple of %s", item, domain.AgendaStep)
+		}
+
+		d := time.Duration(minutes) * time.Minute
+		if d > domain.MaxAppointmentDuration {
+			return nil, fmt.Errorf("type %q: exceeds %s", item, domain.MaxAppointmentDuration)
+		}
+		durations[name] = d
+	}
+	return durations, nil
+}
+
+// DurationFor returns length of appointment type.
+// Unknown types get SlotLength.
+func (s *Schedule) DurationFor(aptType string) time.Duration {
+	if d, ok := s.Durations[strings.ToLower(aptType)]; ok {
+		return d
+	}
+	return s.SlotLength
+}
+
+// IsClosed reports whether day (any time within it) falls in a closure.
+func (s *Schedule) IsClosed(day time.Time) bool {
+	d := midnight(day.In(s.Location))
+	for _, c := range s.Closures {
+		if !d.Before(c.From) && !d.After(c.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingIntervals returns day's working intervals with breaks removed.
+func (s *Schedule) WorkingIntervals(day time.Time) []Interval {
+	day = day.In(s.Location)
+	if s.IsClosed(day) {
+		return nil
+	}
+
+	intervals := s.Hours[day.Weekday()]
+	for _, b := range s.Breaks {
+		intervals = subtract(intervals, b)
+	}
+	return intervals
+}
+
+// DaySlots returns all candidate slots of length d on day, ignoring existing
+// appointments. Slots start every SlotLength plus Buffer within each working
+// interval and, with Buffer after them, end before the next break or closing.
+func (s *Schedule) DaySlots(day time.Time, d time.Duration) []Slot {
+	day = midnight(day.In(s.Location))
+	step := s.SlotLength + s.Buffer
+
+	var slots []Slot
+	for _, iv := range s.WorkingIntervals(day) {
+		end := atMinute(day, iv.End)
+		for t := atMinute(day, iv.Start); !t.Add(d + s.Buffer).After(end); t = t.Add(step) {
+			slots = append(slots, Slot{Start: t, End: t.Add(d)})
+		}
+	}
+	return slots
+}
+
+// parseDays parses "mon" or "mon-fri".
+func parseDays(s string) ([]time.Weekday, error) {
+	from, to, isRange := strings.Cut(strings.ToLower(s), "-")
+	start, ok := weekdayTokens[from]
+	if !ok {
+		return nil, fmt.Errorf("unknown weekday %q", from)
+	}
+	if !isRange {
+		return []time.Weekday{start}, nil
+	}
+
+	end, ok := weekdayTokens[to]
+	if !ok {
+		return nil, fmt.Errorf("unknown weekday %q", to)
+	}
+
+	var days []time.Weekday
+	for d := start; ; d = (d + 1) % 7 {
+		days = append(days, d)
+		if d == end {
+			break
+		}
+	}
+	return days, nil
+}
+
+// parseIntervals parses "08:00-12:00,13:00-18:00".
+func parseIntervals(s string) ([]Interval, error) {
+	var intervals []Interval
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		startStr, endStr, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("range %q: expected HH:MM-HH:MM", part)
+		}
+		start, err := parseClock(startStr)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(endStr)
+		if err != nil {
+			return nil, err
+		}
+		if end <= start {
+			return nil, fmt.Errorf("range %q: end must be after start", part)
+		}
+		if !onAgendaStep(start) || !onAgendaStep(end) {
+			return nil, fmt.Errorf("range %q: times must be multiples of %s", part, domain.AgendaStep)
+		}
+
+		intervals = append(intervals, Interval{Start: start, End: end})
+	}
+	return intervals, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight (24:00 allowed).
+func parseClock(s string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &h, &m); err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// onAgendaStep reports whether minutes fall on domain.AgendaStep.
+func onAgendaStep(minutes int) bool {
+	return time.Duration(minutes)*time.Minute%domain.AgendaStep == 0
+}
+
+// subtract removes b from each interval, splitting where needed.
+func subtract(intervals []Interval, b Interval) []Interval {
+	var out []Interval
+	for _, iv := range intervals {
+		if b.End <= iv.Start || b.Start >= iv.End {
+			out = append(out, iv)
+			continue
+		}
+		if b.Start > iv.Start {
+			out = append(out, Interval{Start: iv.Start, End: b.Start})
+		}
+		if b.End < iv.End {
+			out = append(out, Interval{Start: b.End, End: iv.End})
+		}
+	}
+	return out
+}
+
+// midnight returns start of t's day in t's location.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// atMinute returns day at given minutes since midnight.
+func atMinute(day time.Time, minutes int) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, minutes/60, minutes%60, 0, 0, day.Location())
+}
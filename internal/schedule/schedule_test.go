@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/config"
+)
+
+func TestFromConfigRequiresAgendaSteps(t *testing.T) {
+	valid := config.Config{
+		ClinicHours:       "mon-fri 08:00-18:00",
+		ClinicBreaks:      "12:00-13:00",
+		SlotMinutes:       50,
+		SlotBufferMinutes: 10,
+		AppointmentTypes:  "avaliacao:90",
+		Location:          time.UTC,
+	}
+	if _, err := FromConfig(&valid); err != nil {
+		t.Fatalf("FromConfig(valid): %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+	}{
+		{"slot minutes", func(cfg *config.Config) { cfg.SlotMinutes = 52 }},
+		{"buffer minutes", func(cfg *config.Config) { cfg.SlotBufferMinutes = 7 }},
+		{"type minutes", func(cfg *config.Config) { cfg.AppointmentTypes = "avaliacao:92" }},
+		{"opening hours", func(cfg *config.Config) { cfg.ClinicHours = "mon-fri 08:03-18:00" }},
+		{"break", func(cfg *config.Config) { cfg.ClinicBreaks = "12:00-12:58" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if _, err := FromConfig(&cfg); err == nil {
+				t.Errorf("FromConfig: expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package service
+
+import "errors"
+
+var (
+	// ErrSlotUnavailable is returned when requested time is outside working hours or taken
+	ErrSlotUnavailable = errors.New("slot unavailable")
+
+	// ErrInPast is returned when requested time has already passed
+	ErrInPast = errors.New("time is in the past")
+)
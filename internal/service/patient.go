@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PatientService implements patient registration and lookup.
+type PatientService struct {
+	patients repository.PatientRepository
+}
+
+// NewPatientService creates patient service.
+func NewPatientService(patients repository.PatientRepository) *PatientService {
+	return &PatientService{patients: patients}
+}
+
+// Get retrieves patient by ID.
+func (s *PatientService) Get(ctx context.Context, id primitive.ObjectID) (*domain.Patient, error) {
+	return s.patients.GetByID(ctx, id)
+}
+
+// GetByPhone retrieves patient by phone, ignoring formatting.
+// Returns repository.ErrNotFound if not registered.
+func (s *PatientService) GetByPhone(ctx context.Context, phone string) (*domain.Patient, error) {
+	return s.patients.GetByPhone(ctx, NormalizePhone(phone))
+}
+
+// Register returns patient with phone, creating it with name if missing.
+// A deleted patient with phone is restored instead.
+// Returns repository.ErrInvalidInput if name or phone are invalid.
+func (s *PatientService) Register(ctx context.Context, name, phone string) (*domain.Patient, error) {
+	phone = NormalizePhone(phone)
+	existing, err := s.patients.GetByPhone(ctx, phone)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	patient := &domain.Patient{Name: strings.Join(strings.Fields(name), " "), Phone: phone}
+	err = s.patients.Create(ctx, patient)
+	switch {
+	case err == nil:
+		log.Info().Str("patient_id", patient.ID.Hex()).Msg("patient registered")
+		return patient, nil
+	case errors.Is(err, repository.ErrDuplicate):
+		// Registered concurrently, or deleted; use existing record
+		existing, err := s.patients.GetByPhone(repository.WithDeleted(ctx), phone)
+		if err != nil || !existing.IsDeleted() {
+			return existing, err
+		}
+		if err := s.patients.Restore(ctx, existing.ID); err != nil {
+			return nil, fmt.Errorf("failed to restore patient: %w", err)
+		}
+		log.Info().Str("patient_id", existing.ID.Hex()).Msg("deleted patient registered again, restored")
+		existing.DeletedAt, existing.DeletedBy = time.Time{}, ""
+		return existing, nil
+	default:
+		return nil, err
+	}
+}
+
+// NormalizePhone strips formatting, keeping digits and a leading "+".
+// Addresses that are not phone numbers (e.g. WhatsApp LIDs) are returned unchanged.
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if strings.Contains(phone, "@") {
+		return phone
+	}
+
+	var b strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// erasureReason is recorded on appointments cancelled by an erasure.
+const erasureReason = "data erasure"
+
+// PrivacyService answers data subject requests (LGPD): export of everything
+// stored about a patient and erasure of their personal data.
+// Requests are attributed to the actor of ctx (see repository.WithActor).
+type PrivacyService struct {
+	patients     repository.PatientRepository
+	appointments repository.AppointmentRepository
+	reminders    repository.ReminderRepository
+	sessions     repository.SessionRepository
+	audit        repository.AuditRepository
+	scheduling   *SchedulingService
+	now          func() time.Time
+}
+
+// NewPrivacyService creates privacy service.
+func NewPrivacyService(
+	patients repository.PatientRepository,
+	appointments repository.AppointmentRepository,
+	reminders repository.ReminderRepository,
+	sessions repository.SessionRepository,
+	audit repository.AuditRepository,
+	scheduling *SchedulingService,
+) *PrivacyService {
+	return &PrivacyService{
+		patients:     patients,
+		appointments: appointments,
+		reminders:    reminders,
+		sessions:     sessions,
+		audit:        audit,
+		scheduling:   scheduling,
+		now:          time.Now,
+	}
+}
+
+// Export collects everything stored about patient, deleted or not, and
+// records the export in the audit log. Nothing is returned unless recorded.
+// Returns repository.ErrNotFound if patient does not exist.
+func (s *PrivacyService) Export(ctx context.Context, patientID primitive.ObjectID) (*domain.PatientExport, error) {
+	deleted := repository.WithDeleted(ctx)
+	patient, err := s.patients.GetByID(deleted, patientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	export := &domain.PatientExport{
+		ExportedAt:   s.now().UTC(),
+		Patient:      patient,
+		Appointments: []*domain.Appointment{},
+		Reminders:    []*domain.Reminder{},
+	}
+
+	list := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return s.appointments.ListByPatient(ctx, patient.ID, opts)
+	}
+	for apt, err := range repository.StreamAppointments(deleted, repository.ListOptions{}, list) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list appointments: %w", err)
+		}
+		export.Appointments = append(export.Appointments, apt)
+	}
+
+	for _, apt := range export.Appointments {
+		reminders, err := s.reminders.ListByAppointment(ctx, apt.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reminders: %w", err)
+		}
+		export.Reminders = append(export.Reminders, reminders...)
+	}
+
+	if !patient.IsErased() {
+		session, err := s.sessions.Get(ctx, patient.Phone)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get session: %w", err)
+		}
+		export.Session = session
+	}
+
+	if export.Audit, err = s.history(ctx, patient.ID, export.Appointments); err != nil {
+		return nil, err
+	}
+
+	entry := &domain.AuditEntry{
+		At:            export.ExportedAt,
+		Actor:         repository.ActorFrom(ctx),
+		CorrelationID: repository.CorrelationIDFrom(ctx),
+		Action:        domain.AuditExport,
+		Entity:        domain.EntityPatient,
+		EntityID:      patient.ID,
+	}
+	if err := s.audit.Append(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record export: %w", err)
+	}
+
+	log.Info().
+		Str("patient_id", patient.ID.Hex()).
+		Str("actor", entry.Actor).
+		Msg("patient data exported")
+
+	return export, nil
+}
+
+// Erase anonymizes patient on request (see domain.Patient.Erase). Upcoming
+// appointments are cancelled, as the patient can no longer be reached;
+// all appointments are kept for statistics. The dialog in progress is dropped.
+// Returns repository.ErrNotFound if patient does not exist.
+func (s *PrivacyService) Erase(ctx context.Context, patientID primitive.ObjectID) error {
+	patient, err := s.patients.GetByID(repository.WithDeleted(ctx), patientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	upcoming, err := s.scheduling.Upcoming(ctx, patient.ID)
+	if err != nil {
+		return err
+	}
+	actor := repository.ActorFrom(ctx)
+	for _, apt := range upcoming {
+		if _, err := s.scheduling.Cancel(ctx, apt.ID, actor, erasureReason); err != nil {
+			return fmt.Errorf("failed to cancel appointment: %w", err)
+		}
+	}
+
+	if err := s.sessions.Delete(ctx, patient.Phone); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if err := s.patients.Erase(ctx, patient.ID); err != nil {
+		return fmt.Errorf("failed to erase patient: %w", err)
+	}
+
+	log.Info().
+		Str("patient_id", patient.ID.Hex()).
+		Int("cancelled", len(upcoming)).
+		Str("actor", actor).
+		Msg("patient data erased")
+
+	return nil
+}
+
+// history returns audit entries of patient and their appointments, oldest first.
+func (s *PrivacyService) history(ctx context.Context, patientID primitive.ObjectID, appointments []*domain.Appointment) ([]*domain.AuditEntry, error) {
+	entries, err := s.audit.ListByEntity(ctx, domain.EntityPatient, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	for _, apt := range appointments {
+		aptEntries, err := s.audit.ListByEntity(ctx, domain.EntityAppointment, apt.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list audit entries: %w", err)
+		}
+		entries = append(entries, aptEntries...)
+	}
+
+	slices.SortStableFunc(entries, func(a, b *domain.AuditEntry) int {
+		return a.At.Compare(b.At)
+	})
+	if entries == nil {
+		entries = []*domain.AuditEntry{}
+	}
+	return entries, nil
+}
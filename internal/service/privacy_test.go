@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/audit"
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/sqlite"
+	"github.com/matheusmassa1/clara/internal/schedule"
+)
+
+// openSlots is Availability with every slot free.
+type openSlots struct{}
+
+func (openSlots) FreeSlots(ctx context.Context, from, to time.Time, d time.Duration) ([]schedule.Slot, error) {
+	return nil, nil
+}
+
+func (openSlots) IsFree(ctx context.Context, start time.Time, d time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (openSlots) Duration(aptType string) time.Duration {
+	return 50 * time.Minute
+}
+
+func TestExportAfterEraseHasNoPersonalData(t *testing.T) {
+	ctx := repository.WithActor(context.Background(), domain.ActorStaff)
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "clara.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlite.Close(db) })
+
+	entries := sqlite.NewAuditRepository(db)
+	appointments := audit.NewAppointmentRepository(sqlite.NewAppointmentRepository(db), entries)
+	patients := audit.NewPatientRepository(sqlite.NewPatientRepository(db), appointments, entries)
+	sessions := sqlite.NewSessionRepository(db, time.Hour)
+	scheduling := NewSchedulingService(appointments, openSlots{})
+	privacy := NewPrivacyService(patients, appointments, sqlite.NewReminderRepository(db), sessions, entries, scheduling)
+
+	const name, renamed, phone = "João da Silva", "João da Silva Santos", "+5511999998888"
+	patient, err := NewPatientService(patients).Register(ctx, name, phone)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	patient.Name = renamed
+	if err := patients.Update(ctx, patient); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	apt, err := scheduling.Book(ctx, patient.ID, time.Now().Add(48*time.Hour).Truncate(time.Hour),
+		domain.DefaultAppointmentType, domain.ActorPatient, "booked via whatsapp")
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	session := domain.NewSession(phone)
+	session.Slots["name"] = name
+	if err := sessions.Save(ctx, session); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+
+	if err := privacy.Erase(ctx, patient.ID); err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	export, err := privacy.Export(ctx, patient.ID)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pii := range []string{"João", "Silva", "5511999998888"} {
+		if strings.Contains(string(data), pii) {
+			t.Errorf("export contains %q after erase: %s", pii, data)
+		}
+	}
+
+	if len(export.Appointments) != 1 || export.Appointments[0].ID != apt.ID {
+		t.Errorf("export appointments: got %d, want the booked one kept", len(export.Appointments))
+	} else if export.Appointments[0].Status != domain.StatusCancelled {
+		t.Errorf("erased patient appointment: got status %q, want %q", export.Appointments[0].Status, domain.StatusCancelled)
+	}
+
+	var actions []string
+	for _, entry := range export.Audit {
+		if entry.Entity == domain.EntityPatient {
+			actions = append(actions, entry.Action)
+		}
+	}
+	want := []string{domain.AuditCreate, domain.AuditUpdate, domain.AuditErase}
+	if strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Errorf("patient audit actions: got %v, want %v", actions, want)
+	}
+}
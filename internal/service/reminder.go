@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+)
+
+// ReplyWindow is how long after a reminder is sent a reply that does not
+// quote it is still taken as its answer.
+const ReplyWindow = 12 * time.Hour
+
+// ReminderService resolves which reminder a patient message answers.
+type ReminderService struct {
+	reminders repository.ReminderRepository
+	patients  *PatientService
+	now       func() time.Time
+}
+
+// NewReminderService creates reminder service.
+func NewReminderService(reminders repository.ReminderRepository, patients *PatientService) *ReminderService {
+	return &ReminderService{reminders: reminders, patients: patients, now: time.Now}
+}
+
+// ForReply returns the reminder quoted by a message, or else the sender's
+// most recent reminder for an upcoming appointment sent within ReplyWindow.
+// Returns repository.ErrNotFound if none matches.
+func (s *ReminderService) ForReply(ctx context.Context, sender, quotedID string) (*domain.Reminder, error) {
+	if quotedID != "" {
+		reminder, err := s.reminders.GetByMessageID(ctx, quotedID)
+		if err == nil {
+			return reminder, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get reminder by message: %w", err)
+		}
+		// Quoting some other message: fall back to latest reminder
+	}
+
+	patient, err := s.patients.GetByPhone(ctx, sender)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get patient: %w", err)
+	}
+
+	now := s.now()
+	reminder, err := s.reminders.LatestByPatient(ctx, patient.ID, now)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get latest reminder: %w", err)
+	}
+	if reminder.SentAt.Before(now.Add(-ReplyWindow)) {
+		return nil, repository.ErrNotFound
+	}
+	return reminder, nil
+}
@@ -0,0 +1,227 @@
+// Package service implements clinic use cases on top of repositories.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/schedule"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Availability answers which slots can be booked.
+// Implemented by schedule.Availability.
+type Availability interface {
+	// FreeSlots returns free slots of length d starting in [from, to), ascending.
+	FreeSlots(ctx context.Context, from, to time.Time, d time.Duration) ([]schedule.Slot, error)
+	// IsFree reports whether a slot of length d starting at start is free.
+	IsFree(ctx context.Context, start time.Time, d time.Duration) (bool, error)
+	// Duration returns length of appointment type.
+	Duration(aptType string) time.Duration
+}
+
+// SchedulingService implements booking rules: availability, conflicts and
+// status transitions.
+type SchedulingService struct {
+	appointments repository.AppointmentRepository
+	slots        Availability
+	now          func() time.Time
+}
+
+// NewSchedulingService creates scheduling service.
+func NewSchedulingService(appointments repository.AppointmentRepository, slots Availability) *SchedulingService {
+	return &SchedulingService{
+		appointments: appointments,
+		slots:        slots,
+		now:          time.Now,
+	}
+}
+
+// Get retrieves appointment by ID.
+func (s *SchedulingService) Get(ctx context.Context, id primitive.ObjectID) (*domain.Appointment, error) {
+	return s.appointments.GetByID(ctx, id)
+}
+
+// FreeSlots returns bookable slots of length d starting in [from, to).
+func (s *SchedulingService) FreeSlots(ctx context.Context, from, to time.Time, d time.Duration) ([]schedule.Slot, error) {
+	return s.slots.FreeSlots(ctx, from, to, d)
+}
+
+// Duration returns length of appointment type.
+func (s *SchedulingService) Duration(aptType string) time.Duration {
+	return s.slots.Duration(aptType)
+}
+
+// Upcoming returns patient's pending or confirmed future appointments, soonest first.
+func (s *SchedulingService) Upcoming(ctx context.Context, patientID primitive.ObjectID) ([]*domain.Appointment, error) {
+	list := func(ctx context.Context, opts repository.ListOptions) (*repository.AppointmentPage, error) {
+		return s.appointments.ListByPatient(ctx, patientID, opts)
+	}
+
+	now := s.now()
+	var upcoming []*domain.Appointment
+	for apt, err := range repository.StreamAppointments(ctx, repository.ListOptions{}, list) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list appointments: %w", err)
+		}
+		if apt.IsOpen() && apt.DateTime.After(now) {
+			upcoming = append(upcoming, apt)
+		}
+	}
+	return upcoming, nil
+}
+
+// Book creates pending appointment of aptType for patient at start.
+// Returns ErrInPast or ErrSlotUnavailable if start cannot be booked.
+func (s *SchedulingService) Book(ctx context.Context, patientID primitive.ObjectID, start time.Time, aptType, actor, reason string) (*domain.Appointment, error) {
+	now := s.now()
+	duration := s.slots.Duration(aptType)
+	if err := s.checkSlot(ctx, start, duration, now); err != nil {
+		return nil, err
+	}
+
+	apt := &domain.Appointment{
+		DateTime: start,
+		EndTime:  start.Add(duration),
+		Type:     aptType,
+		Patient:  patientID,
+	}
+	if err := apt.Transition(domain.StatusPending, actor, reason, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.appointments.Create(ctx, apt); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrSlotUnavailable
+		}
+		return nil, fmt.Errorf("failed to create appointment: %w", err)
+	}
+
+	log.Info().
+		Str("appointment_id", apt.ID.Hex()).
+		Str("patient_id", patientID.Hex()).
+		Str("actor", actor).
+		Msg("appointment booked")
+
+	return apt, nil
+}
+
+// Confirm marks appointment confirmed.
+func (s *SchedulingService) Confirm(ctx context.Context, id primitive.ObjectID, actor, reason string) (*domain.Appointment, error) {
+	return s.transition(ctx, id, domain.StatusConfirmed, actor, reason)
+}
+
+// Cancel marks appointment cancelled, freeing its slot.
+func (s *SchedulingService) Cancel(ctx context.Context, id primitive.ObjectID, actor, reason string) (*domain.Appointment, error) {
+	return s.transition(ctx, id, domain.StatusCancelled, actor, reason)
+}
+
+// Complete marks appointment as attended.
+func (s *SchedulingService) Complete(ctx context.Context, id primitive.ObjectID, actor, reason string) (*domain.Appointment, error) {
+	return s.transition(ctx, id, domain.StatusCompleted, actor, reason)
+}
+
+// MarkNoShow marks appointment as missed by patient.
+func (s *SchedulingService) MarkNoShow(ctx context.Context, id primitive.ObjectID, actor, reason string) (*domain.Appointment, error) {
+	return s.transition(ctx, id, domain.StatusNoShow, actor, reason)
+}
+
+// Reschedule moves appointment to start, keeping its type, duration and patient.
+// The original is marked rescheduled and linked to a new pending appointment,
+// which is returned. The new slot must be free of other appointments,
+// including the original one.
+func (s *SchedulingService) Reschedule(ctx context.Context, id primitive.ObjectID, start time.Time, actor, reason string) (*domain.Appointment, error) {
+	orig, err := s.appointments.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckTransition(orig.Status, domain.StatusRescheduled); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := s.checkSlot(ctx, start, orig.Duration(), now); err != nil {
+		return nil, err
+	}
+
+	next := &domain.Appointment{
+		ID:              primitive.NewObjectID(),
+		DateTime:        start,
+		EndTime:         start.Add(orig.Duration()),
+		Type:            orig.Type,
+		Patient:         orig.Patient,
+		RescheduledFrom: orig.ID,
+	}
+	if err := next.Transition(domain.StatusPending, actor, reason, now); err != nil {
+		return nil, err
+	}
+
+	orig.RescheduledTo = next.ID
+	if err := orig.Transition(domain.StatusRescheduled, actor, reason, now); err != nil {
+		return nil, err
+	}
+
+	// Stored together: if either write fails, the original stays intact
+	if err := s.appointments.Reschedule(ctx, orig, next); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrSlotUnavailable
+		}
+		return nil, fmt.Errorf("failed to reschedule appointment: %w", err)
+	}
+
+	log.Info().
+		Str("appointment_id", next.ID.Hex()).
+		Str("rescheduled_from", orig.ID.Hex()).
+		Str("actor", actor).
+		Msg("appointment rescheduled")
+
+	return next, nil
+}
+
+// transition applies status change to stored appointment.
+// Returns *domain.TransitionError if not allowed from current status.
+func (s *SchedulingService) transition(ctx context.Context, id primitive.ObjectID, to, actor, reason string) (*domain.Appointment, error) {
+	apt, err := s.appointments.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := apt.Status
+	if err := apt.Transition(to, actor, reason, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.appointments.Update(ctx, apt); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("appointment_id", apt.ID.Hex()).
+		Str("from", from).
+		Str("to", to).
+		Str("actor", actor).
+		Msg("appointment status changed")
+
+	return apt, nil
+}
+
+// checkSlot returns ErrInPast or ErrSlotUnavailable unless an appointment
+// of length d can be booked at start.
+func (s *SchedulingService) checkSlot(ctx context.Context, start time.Time, d time.Duration, now time.Time) error {
+	if !start.After(now) {
+		return ErrInPast
+	}
+
+	free, err := s.slots.IsFree(ctx, start, d)
+	if err != nil {
+		return fmt.Errorf("failed to check availability: %w", err)
+	}
+	if !free {
+		return ErrSlotUnavailable
+	}
+	return nil
+}
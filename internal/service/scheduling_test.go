@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusmassa1/clara/internal/audit"
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/repository"
+	"github.com/matheusmassa1/clara/internal/repository/memory"
+	"github.com/matheusmassa1/clara/internal/repository/sqlite"
+	"github.com/matheusmassa1/clara/internal/schedule"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestScheduling returns scheduling over the memory repository with
+// mon-fri 08:00-18:00 hours, lunch at 12:00, 50 minute slots, a 10 minute
+// buffer and a 90 minute "avaliacao" type, plus a Monday next week.
+func newTestScheduling(t *testing.T, appointments repository.AppointmentRepository) (*SchedulingService, time.Time) {
+	t.Helper()
+	hours, err := schedule.ParseHours("mon-fri 08:00-18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &schedule.Schedule{
+		Hours:      hours,
+		Breaks:     []schedule.Interval{{Start: 12 * 60, End: 13 * 60}},
+		SlotLength: 50 * time.Minute,
+		Buffer:     10 * time.Minute,
+		Durations: map[string]time.Duration{
+			domain.DefaultAppointmentType: 50 * time.Minute,
+			"avaliacao":                   90 * time.Minute,
+		},
+		Location: time.Local,
+	}
+
+	y, m, d := time.Now().AddDate(0, 0, 7).Date()
+	monday := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	return NewSchedulingService(appointments, schedule.NewAvailability(s, appointments)), monday
+}
+
+func TestBookChecksWholeDuration(t *testing.T) {
+	ctx := context.Background()
+	scheduling, monday := newTestScheduling(t, memory.NewAppointmentRepository())
+	patient := primitive.NewObjectID()
+	at := func(hour int) time.Time { return monday.Add(time.Duration(hour) * time.Hour) }
+
+	if _, err := scheduling.Book(ctx, patient, at(10), domain.DefaultAppointmentType, domain.ActorStaff, ""); err != nil {
+		t.Fatalf("book 10:00: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		aptType string
+		want    error
+	}{
+		{"overlaps next appointment", at(9), "avaliacao", ErrSlotUnavailable},
+		{"runs into lunch", at(11), "avaliacao", ErrSlotUnavailable},
+		{"runs past closing", at(17), "avaliacao", ErrSlotUnavailable},
+		{"fits", at(14), "avaliacao", nil},
+		{"short type fits before appointment", at(9), domain.DefaultAppointmentType, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apt, err := scheduling.Book(ctx, patient, tt.start, tt.aptType, domain.ActorStaff, "")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Book(%s, %s) error = %v, want %v", tt.start.Format("15:04"), tt.aptType, err, tt.want)
+			}
+			if err == nil && apt.Duration() != scheduling.Duration(tt.aptType) {
+				t.Errorf("Duration = %s, want %s", apt.Duration(), scheduling.Duration(tt.aptType))
+			}
+		})
+	}
+}
+
+// staleReads returns appointments as they were when it was created,
+// simulating a status change made between read and write.
+type staleReads struct {
+	repository.AppointmentRepository
+	snapshot map[primitive.ObjectID]domain.Appointment
+}
+
+func (r *staleReads) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Appointment, error) {
+	apt, ok := r.snapshot[id]
+	if !ok {
+		return r.AppointmentRepository.GetByID(ctx, id)
+	}
+	return &apt, nil
+}
+
+func TestRescheduleFailureLeavesNoTrace(t *testing.T) {
+	ctx := repository.WithActor(context.Background(), domain.ActorStaff)
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "clara.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlite.Close(db) })
+
+	entries := sqlite.NewAuditRepository(db)
+	appointments := audit.NewAppointmentRepository(sqlite.NewAppointmentRepository(db), entries)
+	scheduling, monday := newTestScheduling(t, appointments)
+	at := func(hour int) time.Time { return monday.Add(time.Duration(hour) * time.Hour) }
+
+	apt, err := scheduling.Book(ctx, primitive.NewObjectID(), at(9), domain.DefaultAppointmentType, domain.ActorPatient, "")
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	stale := &staleReads{AppointmentRepository: appointments, snapshot: map[primitive.ObjectID]domain.Appointment{apt.ID: *apt}}
+	if _, err := scheduling.Cancel(ctx, apt.ID, domain.ActorStaff, ""); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	// Original was cancelled meanwhile: its update fails after the new one is written
+	scheduling.appointments = stale
+	if _, err := scheduling.Reschedule(ctx, apt.ID, at(14), domain.ActorPatient, ""); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("reschedule: got %v, want %v", err, domain.ErrInvalidTransition)
+	}
+
+	page, err := appointments.ListByDateRange(repository.WithDeleted(ctx), at(0), at(24), repository.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := page.Appointments
+	if len(got) != 1 || got[0].ID != apt.ID || got[0].Status != domain.StatusCancelled {
+		t.Errorf("appointments after failed reschedule: got %d, want only the cancelled original", len(got))
+	}
+
+	// Booking and cancelling the original only
+	var entryCount int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log`).Scan(&entryCount); err != nil {
+		t.Fatal(err)
+	}
+	if entryCount != 2 {
+		t.Errorf("audit entries: got %d, want 2", entryCount)
+	}
+}
+
+func TestRescheduleIsAuditedOnce(t *testing.T) {
+	ctx := repository.WithActor(context.Background(), domain.ActorStaff)
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "clara.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlite.Close(db) })
+
+	entries := sqlite.NewAuditRepository(db)
+	appointments := audit.NewAppointmentRepository(sqlite.NewAppointmentRepository(db), entries)
+	scheduling, monday := newTestScheduling(t, appointments)
+	at := func(hour int) time.Time { return monday.Add(time.Duration(hour) * time.Hour) }
+
+	apt, err := scheduling.Book(ctx, primitive.NewObjectID(), at(9), "avaliacao", domain.ActorPatient, "")
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	if _, err := scheduling.Reschedule(ctx, apt.ID, at(11), domain.ActorPatient, ""); !errors.Is(err, ErrSlotUnavailable) {
+		t.Fatalf("reschedule into lunch: got %v, want %v", err, ErrSlotUnavailable)
+	}
+	next, err := scheduling.Reschedule(ctx, apt.ID, at(14), domain.ActorPatient, "")
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if next.Duration() != apt.Duration() || next.RescheduledFrom != apt.ID {
+		t.Errorf("rescheduled: got %s from %s, want %s from %s", next.Duration(), next.RescheduledFrom.Hex(), apt.Duration(), apt.ID.Hex())
+	}
+
+	for _, tt := range []struct {
+		id   primitive.ObjectID
+		want []string
+	}{
+		{apt.ID, []string{domain.AuditCreate, domain.AuditUpdate}},
+		{next.ID, []string{domain.AuditCreate}},
+	} {
+		list, err := entries.ListByEntity(ctx, domain.EntityAppointment, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actions []string
+		for _, entry := range list {
+			actions = append(actions, entry.Action)
+		}
+		if strings.Join(actions, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("audit actions of %s: got %v, want %v", tt.id.Hex(), actions, tt.want)
+		}
+	}
+}
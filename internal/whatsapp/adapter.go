@@ -0,0 +1,145 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/matheusmassa1/clara/internal/domain"
+)
+
+// toMessage converts whatsmeow message event into clara message.
+// Returns nil if event carries neither text nor media.
+func toMessage(evt *events.Message) *domain.Message {
+	msg := &domain.Message{
+		ID:         evt.Info.ID,
+		Sender:     senderAddress(evt.Info.MessageSource),
+		Chat:       evt.Info.Chat.String(),
+		ReceivedAt: evt.Info.Timestamp,
+	}
+
+	m := evt.Message
+	if m == nil {
+		return nil
+	}
+
+	var ctxInfo *waProto.ContextInfo
+	switch {
+	case m.GetConversation() != "":
+		msg.Text = m.GetConversation()
+	case m.ExtendedTextMessage != nil:
+		msg.Text = m.ExtendedTextMessage.GetText()
+		ctxInfo = m.ExtendedTextMessage.GetContextInfo()
+	case m.ImageMessage != nil:
+		img := m.ImageMessage
+		msg.Text = img.GetCaption()
+		msg.Media = &domain.Media{Kind: domain.MediaImage, MimeType: img.GetMimetype(), Ref: img.GetDirectPath()}
+		ctxInfo = img.GetContextInfo()
+	case m.AudioMessage != nil:
+		aud := m.AudioMessage
+		msg.Media = &domain.Media{Kind: domain.MediaAudio, MimeType: aud.GetMimetype(), Ref: aud.GetDirectPath()}
+		ctxInfo = aud.GetContextInfo()
+	case m.VideoMessage != nil:
+		vid := m.VideoMessage
+		msg.Text = vid.GetCaption()
+		msg.Media = &domain.Media{Kind: domain.MediaVideo, MimeType: vid.GetMimetype(), Ref: vid.GetDirectPath()}
+		ctxInfo = vid.GetContextInfo()
+	case m.DocumentMessage != nil:
+		doc := m.DocumentMessage
+		msg.Text = doc.GetCaption()
+		msg.Media = &domain.Media{
+			Kind:     domain.MediaDocument,
+			MimeType: doc.GetMimetype(),
+			FileName: doc.GetFileName(),
+			Ref:      doc.GetDirectPath(),
+		}
+		ctxInfo = doc.GetContextInfo()
+	case m.ButtonsResponseMessage != nil:
+		// Quick-reply tap: use button label as text
+		btn := m.ButtonsResponseMessage
+		msg.Text = btn.GetSelectedDisplayText()
+		ctxInfo = btn.GetContextInfo()
+	case m.TemplateButtonReplyMessage != nil:
+		btn := m.TemplateButtonReplyMessage
+		msg.Text = btn.GetSelectedDisplayText()
+		ctxInfo = btn.GetContextInfo()
+	case m.ListResponseMessage != nil:
+		item := m.ListResponseMessage
+		msg.Text = item.GetTitle()
+		ctxInfo = item.GetContextInfo()
+	case m.StickerMessage != nil:
+		st := m.StickerMessage
+		msg.Media = &domain.Media{Kind: domain.MediaSticker, MimeType: st.GetMimetype(), Ref: st.GetDirectPath()}
+		ctxInfo = st.GetContextInfo()
+	}
+
+	if ctxInfo != nil && ctxInfo.GetStanzaID() != "" {
+		msg.Quoted = &domain.Quoted{
+			ID:   ctxInfo.GetStanzaID(),
+			Text: messageText(ctxInfo.GetQuotedMessage()),
+		}
+	}
+
+	if strings.TrimSpace(msg.Text) == "" && msg.Media == nil {
+		return nil
+	}
+
+	return msg
+}
+
+// senderAddress returns sender phone in E.164 when resolvable.
+// LID senders fall back to phone-number alt address, then raw JID.
+func senderAddress(src types.MessageSource) string {
+	jid := src.Sender
+	if jid.Server == types.HiddenUserServer && src.SenderAlt.Server == types.DefaultUserServer {
+		jid = src.SenderAlt
+	}
+
+	if jid.Server == types.DefaultUserServer {
+		return "+" + jid.User
+	}
+	return jid.ToNonAD().String()
+}
+
+// messageText extracts plain text from message (conversation or extended text).
+func messageText(m *waProto.Message) string {
+	if m == nil {
+		return ""
+	}
+	if text := m.GetConversation(); text != "" {
+		return text
+	}
+	return m.GetExtendedTextMessage().GetText()
+}
+
+// parseAddress converts clara address (E.164 phone or JID string) into JID.
+func parseAddress(addr string) (types.JID, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return types.JID{}, fmt.Errorf("empty address")
+	}
+
+	if strings.Contains(addr, "@") {
+		jid, err := types.ParseJID(addr)
+		if err != nil {
+			return types.JID{}, fmt.Errorf("invalid jid %q: %w", addr, err)
+		}
+		return jid, nil
+	}
+
+	// Phone number: keep digits only
+	var b strings.Builder
+	for _, r := range addr {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return types.JID{}, fmt.Errorf("invalid phone %q", addr)
+	}
+
+	return types.NewJID(b.String(), types.DefaultUserServer), nil
+}
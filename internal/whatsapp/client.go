@@ -17,19 +17,27 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/matheusmassa1/clara/internal/config"
+	"github.com/matheusmassa1/clara/internal/domain"
+	"github.com/matheusmassa1/clara/internal/handler"
 )
 
 // Client wraps whatsmeow client with app-specific logic.
 type Client struct {
-	client *whatsmeow.Client
-	cfg    *config.Config
-	logger zerolog.Logger
-	store  *sqlstore.Container
+	client  *whatsmeow.Client
+	cfg     *config.Config
+	logger  zerolog.Logger
+	store   *sqlstore.Container
+	handler handler.MessageHandler
 }
 
 // New creates WhatsApp client instance.
 // Initializes SQLite store for session persistence.
-func New(cfg *config.Config, logger zerolog.Logger) (*Client, error) {
+// Inbound messages are dispatched to h.
+func New(cfg *config.Config, logger zerolog.Logger, h handler.MessageHandler) (*Client, error) {
+	if h == nil {
+		return nil, fmt.Errorf("message handler is required")
+	}
+
 	// Setup store
 	dbLog := waLog.Stdout("Database", "ERROR", true)
 	ctx := context.Background()
@@ -39,9 +47,10 @@ func New(cfg *config.Config, logger zerolog.Logger) (*Client, error) {
 	}
 
 	return &Client{
-		cfg:    cfg,
-		logger: logger,
-		store:  store,
+		cfg:     cfg,
+		logger:  logger,
+		store:   store,
+		handler: h,
 	}, nil
 }
 
@@ -116,27 +125,85 @@ func (c *Client) Disconnect() {
 }
 
 // SendText sends text message to JID.
-func (c *Client) SendText(jid types.JID, text string) error {
+// Returns sent message ID.
+func (c *Client) SendText(jid types.JID, text string) (string, error) {
 	if c.client == nil || !c.client.IsConnected() {
-		return ErrDisconnected
+		return "", ErrDisconnected
 	}
 
-	_, err := c.client.SendMessage(context.Background(), jid, &waProto.Message{
+	resp, err := c.client.SendMessage(context.Background(), jid, &waProto.Message{
 		Conversation: proto.String(text),
 	})
 	if err != nil {
 		if isNetworkError(err) {
-			return wrapNetworkError(err, "failed to send message")
+			return "", wrapNetworkError(err, "failed to send message")
 		}
-		return wrapProtocolError(err, "failed to send message")
+		return "", wrapProtocolError(err, "failed to send message")
 	}
 
 	c.logger.Debug().
 		Str("jid", jid.String()).
+		Str("message_id", resp.ID).
 		Str("text", text).
 		Msg("message sent")
 
-	return nil
+	return resp.ID, nil
+}
+
+// SendDocument uploads doc and sends it to JID with caption.
+// Returns sent message ID.
+func (c *Client) SendDocument(jid types.JID, doc *domain.Document, caption string) (string, error) {
+	if c.client == nil || !c.client.IsConnected() {
+		return "", ErrDisconnected
+	}
+
+	upload, err := c.client.Upload(context.Background(), doc.Data, whatsmeow.MediaDocument)
+	if err != nil {
+		if isNetworkError(err) {
+			return "", wrapNetworkError(err, "failed to upload document")
+		}
+		return "", wrapProtocolError(err, "failed to upload document")
+	}
+
+	resp, err := c.client.SendMessage(context.Background(), jid, &waProto.Message{
+		DocumentMessage: &waProto.DocumentMessage{
+			URL:           proto.String(upload.URL),
+			DirectPath:    proto.String(upload.DirectPath),
+			MediaKey:      upload.MediaKey,
+			FileEncSHA256: upload.FileEncSHA256,
+			FileSHA256:    upload.FileSHA256,
+			FileLength:    proto.Uint64(upload.FileLength),
+			Mimetype:      proto.String(doc.MimeType),
+			FileName:      proto.String(doc.FileName),
+			Title:         proto.String(doc.FileName),
+			Caption:       proto.String(caption),
+		},
+	})
+	if err != nil {
+		if isNetworkError(err) {
+			return "", wrapNetworkError(err, "failed to send document")
+		}
+		return "", wrapProtocolError(err, "failed to send document")
+	}
+
+	c.logger.Debug().
+		Str("jid", jid.String()).
+		Str("message_id", resp.ID).
+		Str("file_name", doc.FileName).
+		Int("size", len(doc.Data)).
+		Msg("document sent")
+
+	return resp.ID, nil
+}
+
+// SendTo sends text message to address (phone number or JID string).
+// Returns sent message ID.
+func (c *Client) SendTo(to, text string) (string, error) {
+	jid, err := parseAddress(to)
+	if err != nil {
+		return "", err
+	}
+	return c.SendText(jid, text)
 }
 
 // Reconnect attempts reconnection with exponential backoff.
@@ -1,12 +1,15 @@
 package whatsapp
 
 import (
+	"context"
+
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
 // handleMessage processes incoming WhatsApp messages.
 // Filters: 1-on-1 only (ignores groups).
-// Echo handler: replies with "Echo: {text}".
+// Converts event to clara message and sends replies returned by the handler.
 func (c *Client) handleMessage(evt *events.Message) {
 	// Ignore group messages (only process 1-on-1 chats)
 	// s.whatsapp.net = regular 1-on-1
@@ -25,49 +28,73 @@ func (c *Client) handleMessage(evt *events.Message) {
 		return
 	}
 
-	// Extract message text
-	text := evt.Message.GetConversation()
-	if text == "" {
-		// Try extended text message
-		if evt.Message.ExtendedTextMessage != nil {
-			text = evt.Message.ExtendedTextMessage.GetText()
-		}
-	}
+	msg := toMessage(evt)
 
 	// Ignore empty messages
-	if text == "" {
+	if msg == nil {
 		c.logger.Info().Msg("ignoring empty message")
 		return
 	}
 
 	c.logger.Info().
-		Str("from", evt.Info.Sender.String()).
-		Str("text", text).
+		Str("from", msg.Sender).
+		Str("text", msg.Text).
 		Msg("received message")
 
-	// Echo handler: reply with "Clara: Testing"
-	reply := "Clara: Testing"
-
-	if err := c.SendText(evt.Info.Sender, reply); err != nil {
+	replies, err := c.handler.Handle(context.Background(), msg)
+	if err != nil {
 		c.logger.Error().
 			Err(err).
-			Str("from", evt.Info.Sender.String()).
-			Msg("failed to send echo reply")
+			Str("from", msg.Sender).
+			Msg("handler failed")
+		c.sendErrorReply(evt.Info.Chat)
+		return
+	}
 
-		// If configured, send error reply to user
-		if c.cfg.WAReplyOnError {
-			errReply := "Erro ao processar mensagem"
-			if sendErr := c.SendText(evt.Info.Sender, errReply); sendErr != nil {
+	for _, reply := range replies {
+		to := evt.Info.Chat
+		if reply.To != "" {
+			to, err = parseAddress(reply.To)
+			if err != nil {
 				c.logger.Error().
-					Err(sendErr).
-					Msg("failed to send error reply")
+					Err(err).
+					Str("to", reply.To).
+					Msg("invalid reply address")
+				continue
 			}
 		}
+
+		if reply.Document != nil {
+			_, err = c.SendDocument(to, reply.Document, reply.Text)
+		} else {
+			_, err = c.SendText(to, reply.Text)
+		}
+		if err != nil {
+			c.logger.Error().
+				Err(err).
+				Str("to", to.String()).
+				Msg("failed to send reply")
+			c.sendErrorReply(evt.Info.Chat)
+			return
+		}
+
+		c.logger.Debug().
+			Str("to", to.String()).
+			Str("reply", reply.Text).
+			Msg("reply sent")
+	}
+}
+
+// sendErrorReply notifies chat of processing failure, if configured.
+func (c *Client) sendErrorReply(chat types.JID) {
+	if !c.cfg.WAReplyOnError {
 		return
 	}
 
-	c.logger.Debug().
-		Str("to", evt.Info.Sender.String()).
-		Str("reply", reply).
-		Msg("echo reply sent")
+	errReply := "Erro ao processar mensagem"
+	if _, err := c.SendText(chat, errReply); err != nil {
+		c.logger.Error().
+			Err(err).
+			Msg("failed to send error reply")
+	}
 }